@@ -1,34 +1,123 @@
 package main
 
 import (
-	"log"
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/saifulwebid/gotodo"
 	"github.com/saifulwebid/gotodo/database"
-	"github.com/subosito/gotenv"
 
 	"github.com/saifulwebid/gotodoapp/cli"
+	"github.com/saifulwebid/gotodoapp/config"
+	"github.com/saifulwebid/gotodoapp/httpclient"
+	"github.com/saifulwebid/gotodoapp/profile"
+	"github.com/saifulwebid/gotodoapp/timestamps"
 )
 
-func init() {
-	gotenv.Load()
+func main() {
+	os.Exit(run())
 }
 
-func main() {
-	db, err := database.NewRepository()
+// run runs gotodocli and returns its exit code, so that deferred cleanup
+// happens before exiting.
+func run() int {
+	cfg, err := config.Load(config.Options{})
 	if err != nil {
-		log.Fatal(err)
+		fmt.Fprintln(os.Stderr, "gotodocli:", err)
+		return cli.ExitUsage
 	}
 
-	service := gotodo.NewService(db)
+	profilesFile, err := profile.DefaultPath()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gotodocli:", err)
+		return cli.ExitFailure
+	}
+
+	var closers []io.Closer
+	defer func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}()
 
 	app := &cli.Application{
-		Service: service,
+		Config:       cfg,
+		ProfilesFile: profilesFile,
+		Connect: func(p *profile.Profile) (gotodo.Service, error) {
+			service, c, err := connect(cfg, p)
+			closers = c
+
+			return service, err
+		},
+	}
+
+	return cli.ExitCode(app.Run(os.Args))
+}
+
+// connect returns the Service of profile p, or of cfg if p is nil, and what
+// to close once done with it. The database settings of p take precedence over
+// those of cfg.
+func connect(cfg *config.Config, p *profile.Profile) (gotodo.Service, []io.Closer, error) {
+	if p != nil && p.Backend == profile.BackendHTTP {
+		client, err := httpclient.NewClient(p.URL)
+		if err != nil {
+			return nil, nil, err
+		}
+		client.Token = p.Token
+		client.OnError = func(method string, err error) {
+			fmt.Fprintf(os.Stderr, "gotodocli: %s: %v\n", method, err)
+		}
+
+		return client, nil, nil
+	}
+
+	dbCfg := *cfg
+	if p != nil {
+		if p.DB.Host != "" {
+			dbCfg.DBHost = p.DB.Host
+		}
+		if p.DB.Port != 0 {
+			dbCfg.DBPort = p.DB.Port
+		}
+		if p.DB.Name != "" {
+			dbCfg.DBName = p.DB.Name
+		}
+		if p.DB.User != "" {
+			dbCfg.DBUser = p.DB.User
+		}
+		if p.DB.Pass != "" {
+			dbCfg.DBPass = p.DB.Pass
+		}
+	}
+
+	if err := dbCfg.ExportDatabaseEnv(); err != nil {
+		return nil, nil, err
 	}
 
-	err = app.Run(os.Args)
+	db, err := database.NewRepository()
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, err
 	}
+
+	var closers []io.Closer
+	if c, ok := interface{}(db).(io.Closer); ok {
+		closers = append(closers, c)
+	}
+
+	svc := gotodo.NewService(db)
+
+	times, err := timestamps.OpenSQL(timestamps.MySQLDSN(dbCfg.DBHost, dbCfg.DBPort, dbCfg.DBName, dbCfg.DBUser, dbCfg.DBPass))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gotodocli: timestamps of todos are not recorded:", err)
+		return svc, closers, nil
+	}
+	closers = append(closers, times)
+
+	ts := timestamps.NewService(svc, times)
+	ts.OnError = func(method string, err error) {
+		fmt.Fprintf(os.Stderr, "gotodocli: %s: cannot record the timestamps of a todo: %v\n", method, err)
+	}
+
+	return ts, closers, nil
 }
@@ -1,28 +1,213 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 
-	"github.com/subosito/gotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
 
 	"github.com/saifulwebid/gotodo"
 	"github.com/saifulwebid/gotodo/database"
+	"github.com/saifulwebid/gotodoapp/config"
 	"github.com/saifulwebid/gotodoapp/handler"
+	"github.com/saifulwebid/gotodoapp/logging"
+	"github.com/saifulwebid/gotodoapp/rpc"
+	"github.com/saifulwebid/gotodoapp/server"
+	"github.com/saifulwebid/gotodoapp/timestamps"
 )
 
-func init() {
-	gotenv.Load()
+func loadConfig(args []string) (cfg *config.Config, printOnly bool, err error) {
+	fs := flag.NewFlagSet("gotodoserver", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: gotodoserver [config print] [flags]")
+		fs.PrintDefaults()
+	}
+	file := fs.String("config", "", "path of a TOML or YAML configuration file (GOTODO_CONFIG)")
+	envFile := fs.String("env-file", ".env", "path of a .env file")
+	flags := config.RegisterFlags(fs)
+
+	if len(args) >= 2 && args[0] == "config" && args[1] == "print" {
+		printOnly = true
+		args = args[2:]
+	}
+
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err = config.Load(config.Options{
+		File:    *file,
+		EnvFile: *envFile,
+		Flags:   flags(),
+	})
+
+	return cfg, printOnly, err
+}
+
+func serverConfig(cfg *config.Config) server.Config {
+	sc := server.DefaultConfig()
+
+	sc.Addr = ":" + strconv.Itoa(cfg.APIPort)
+	sc.ReadTimeout = cfg.APIReadTimeout
+	sc.ReadHeaderTimeout = cfg.APIReadHeaderTimeout
+	sc.WriteTimeout = cfg.APIWriteTimeout
+	sc.IdleTimeout = cfg.APIIdleTimeout
+	sc.MaxHeaderBytes = cfg.APIMaxHeaderBytes
+	sc.ShutdownTimeout = cfg.APIShutdownTimeout
+
+	sc.TLSCertFile = cfg.TLSCertFile
+	sc.TLSKeyFile = cfg.TLSKeyFile
+	sc.TLSReloadInterval = cfg.TLSReloadInterval
+	sc.TLSClientCAFile = cfg.TLSClientCAFile
+	if cfg.TLSRedirectPort != 0 {
+		sc.RedirectAddr = ":" + strconv.Itoa(cfg.TLSRedirectPort)
+	}
+
+	return sc
 }
 
 func main() {
-	db, err := database.NewRepository()
+	cfg, printOnly, err := loadConfig(os.Args[1:])
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	sv := handler.NewServer(gotodo.NewService(db))
+	if printOnly {
+		if err := cfg.Print(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	logger, err := logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var identities map[string]string
+	if cfg.TLSClientIdentitiesFile != "" {
+		identities, err = handler.LoadClientIdentities(cfg.TLSClientIdentitiesFile)
+		if err != nil {
+			logger.Error("cannot load client identities", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := cfg.ExportDatabaseEnv(); err != nil {
+		logger.Error("cannot configure the database", "error", err)
+		os.Exit(1)
+	}
 
-	log.Fatal(http.ListenAndServe(":"+os.Getenv("GOTODO_API_PORT"), sv))
+	db, err := database.NewRepository()
+	if err != nil {
+		logger.Error("cannot connect to the database", "error", err)
+		os.Exit(1)
+	}
+
+	var svc gotodo.Service = gotodo.NewService(db)
+	probe := handler.ServiceProbe(svc)
+
+	var timed timestamps.Timed
+	times, err := timestamps.OpenSQL(timestamps.MySQLDSN(cfg.DBHost, cfg.DBPort, cfg.DBName, cfg.DBUser, cfg.DBPass))
+	if err != nil {
+		logger.Warn("timestamps of todos are not recorded", "error", err)
+	} else {
+		ts := timestamps.NewService(svc, times)
+		ts.OnError = func(method string, err error) {
+			logger.Warn("cannot record the timestamps of a todo", "method", method, "error", err)
+		}
+		svc, timed = ts, ts
+	}
+
+	metrics := handler.NewMetrics(prometheus.DefaultRegisterer)
+	metrics.WatchTodos(svc)
+
+	instrumented := handler.LogService(logger, metrics.InstrumentService(svc))
+
+	api := handler.NewServer(instrumented)
+	api.Probe = probe
+	api.CacheMaxAge = cfg.APICacheMaxAge
+	api.Times = timed
+	api.Handle("/metrics", promhttp.Handler())
+
+	var sv http.Handler = handler.RequireToken(cfg.APIAuthToken, api)
+	if cfg.CompressionEnabled {
+		sv = handler.Compress(cfg.CompressionMinSize, sv)
+	}
+	if cfg.APIValidateRequests {
+		sv = handler.NewRequestValidator(sv)
+	}
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		sv = handler.CORS(handler.CORSOptions{
+			AllowedOrigins:   cfg.CORSAllowedOrigins,
+			AllowedMethods:   cfg.CORSAllowedMethods,
+			AllowedHeaders:   cfg.CORSAllowedHeaders,
+			ExposedHeaders:   cfg.CORSExposedHeaders,
+			AllowCredentials: cfg.CORSAllowCredentials,
+			MaxAge:           cfg.CORSMaxAge,
+		}, sv)
+	}
+	if cfg.RateLimitEnabled {
+		limiter, err := handler.NewRateLimiter(handler.RateLimitOptions{
+			ReadRate:       cfg.RateLimitReadRate,
+			ReadBurst:      cfg.RateLimitReadBurst,
+			WriteRate:      cfg.RateLimitWriteRate,
+			WriteBurst:     cfg.RateLimitWriteBurst,
+			TrustedProxies: cfg.RateLimitTrustedProxies,
+			EvictAfter:     cfg.RateLimitEvictAfter,
+		})
+		if err != nil {
+			logger.Error("cannot configure rate limiting", "error", err)
+			os.Exit(1)
+		}
+		if cfg.RateLimitEvictAfter > 0 {
+			limiter.StartEviction(context.Background(), cfg.RateLimitEvictAfter)
+		}
+		sv = limiter.Wrap(sv)
+	}
+	sv = handler.ClientCertIdentity(identities, sv)
+	sv = metrics.Wrap(sv)
+	sv = handler.LogRequests(logger, sv)
+
+	runner := &server.Runner{
+		Config:  serverConfig(cfg),
+		Handler: sv,
+		Logger:  logger,
+	}
+	if cfg.GRPCPort != 0 {
+		var opts []grpc.ServerOption
+		if cfg.APIAuthToken != "" {
+			opts = append(opts, grpc.UnaryInterceptor(rpc.RequireToken(cfg.APIAuthToken)))
+		}
+		gs := grpc.NewServer(opts...)
+		rs := rpc.NewServer(instrumented)
+		rs.OnWrite = api.Touch
+		rs.Register(gs)
+		runner.Sidecars = append(runner.Sidecars, server.Sidecar{
+			Name:    "grpc",
+			Addr:    ":" + strconv.Itoa(cfg.GRPCPort),
+			Backend: gs,
+		})
+	}
+	if c, ok := interface{}(db).(io.Closer); ok {
+		runner.Closers = append(runner.Closers, c)
+	}
+	if times != nil {
+		runner.Closers = append(runner.Closers, times)
+	}
+
+	if err := runner.Run(context.Background()); err != nil {
+		logger.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+// Formats of the todos read by add-many.
+const (
+	// formatAuto tells the format of each line: JSON, a Markdown checklist
+	// item, or a plain title.
+	formatAuto = "auto"
+	// formatLines reads a title per line.
+	formatLines = "lines"
+	// formatMarkdown reads the items of Markdown checklists, ignoring the
+	// other lines.
+	formatMarkdown = "markdown"
+	// formatJSON reads a JSON object per line.
+	formatJSON = "jsonl"
+)
+
+// checklistItem matches Markdown checklist items, e.g. "- [ ] Buy milk" or
+// "* [x] Buy eggs".
+var checklistItem = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.*)$`)
+
+// parseTodoLine parses a line read by add-many in format. It returns nil if
+// the line holds no todo.
+func parseTodoLine(line string, format string) (*gotodo.Todo, error) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return nil, nil
+	}
+
+	if format == formatJSON || (format == formatAuto && strings.HasPrefix(trimmed, "{")) {
+		var item struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Done        bool   `json:"done"`
+		}
+		if err := json.Unmarshal([]byte(trimmed), &item); err != nil {
+			return nil, validationError("invalid JSON: %v", err)
+		}
+
+		return &gotodo.Todo{Title: item.Title, Description: item.Description, Done: item.Done}, nil
+	}
+
+	if format == formatAuto || format == formatMarkdown {
+		if m := checklistItem.FindStringSubmatch(line); m != nil {
+			return &gotodo.Todo{Title: strings.TrimSpace(m[2]), Done: m[1] != " "}, nil
+		}
+		if format == formatMarkdown {
+			return nil, nil
+		}
+	}
+
+	return &gotodo.Todo{Title: trimmed}, nil
+}
+
+// stdin returns where add-many reads todos from.
+func (a *Application) stdin() io.Reader {
+	if a.In == nil {
+		return os.Stdin
+	}
+
+	return a.In
+}
+
+// addMany creates the todos read from stdin, reporting the lines which fail
+// and carrying on.
+func (a *Application) addMany(c *cli.Context) error {
+	format := c.String("format")
+	switch format {
+	case formatAuto, formatLines, formatMarkdown, formatJSON:
+	default:
+		return usageError("format %q must be one of %s, %s, %s or %s", format, formatAuto, formatLines, formatMarkdown, formatJSON)
+	}
+
+	if a.In == nil && a.inShell {
+		return usageError("todos can not be read from stdin in the shell")
+	}
+	if a.In == nil && stdinIsTerminal() {
+		fmt.Fprintln(a.stderr(), "Reading todos from stdin, one per line; end with Ctrl+D.")
+	}
+
+	dryRun := c.Bool("dry-run")
+	created, failed, code := 0, 0, 0
+	fail := func(n int, err error) {
+		writeError(a.stderr(), fmt.Errorf("line %d: %w", n, err), a.jsonErrors)
+		failed++
+		if code == 0 {
+			code = ExitCode(err)
+		} else if code != ExitCode(err) {
+			code = ExitFailure
+		}
+	}
+
+	scanner := bufio.NewScanner(a.stdin())
+	for n := 1; scanner.Scan(); n++ {
+		todo, err := parseTodoLine(scanner.Text(), format)
+		if err == nil && todo != nil {
+			err = validateTitle(todo.Title)
+		}
+		if err != nil {
+			fail(n, err)
+			continue
+		}
+		if todo == nil {
+			continue
+		}
+
+		if dryRun {
+			a.printTodo("Would create todo:", todo)
+			created++
+			continue
+		}
+
+		done := todo.Done
+		todo, err = a.Service.Add(todo.Title, todo.Description)
+		if err == nil && done {
+			err = a.Service.MarkAsDone(todo)
+		}
+		if err != nil {
+			fail(n, storageError(err))
+			continue
+		}
+
+		a.printTodo("Created todo:", todo)
+		created++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return &Error{Code: code, Err: fmt.Errorf("%d of %d todos failed", failed, created+failed)}
+	}
+	if created == 0 {
+		a.printMessage("no todo to create")
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+func TestParseTodoLine(t *testing.T) {
+	for _, tc := range []struct {
+		line   string
+		format string
+		todo   *gotodo.Todo
+		err    string
+	}{
+		{"  ", formatAuto, nil, ""},
+		{"Buy milk ", formatAuto, &gotodo.Todo{Title: "Buy milk"}, ""},
+		{"src/main.go:12:	// TODO: close the file", formatAuto, &gotodo.Todo{Title: "src/main.go:12:	// TODO: close the file"}, ""},
+		{"- [ ] Buy milk", formatAuto, &gotodo.Todo{Title: "Buy milk"}, ""},
+		{"  * [x] Buy eggs", formatAuto, &gotodo.Todo{Title: "Buy eggs", Done: true}, ""},
+		{`{"title": "Buy bread", "description": "Whole wheat", "done": true}`, formatAuto, &gotodo.Todo{Title: "Buy bread", Description: "Whole wheat", Done: true}, ""},
+		{"- [ ] Buy milk", formatLines, &gotodo.Todo{Title: "- [ ] Buy milk"}, ""},
+		{"{not JSON}", formatLines, &gotodo.Todo{Title: "{not JSON}"}, ""},
+		{"## Action items", formatMarkdown, nil, ""},
+		{"- [X] Send the minutes", formatMarkdown, &gotodo.Todo{Title: "Send the minutes", Done: true}, ""},
+		{"Buy milk", formatJSON, nil, "invalid JSON"},
+		{`{"title": 42}`, formatAuto, nil, "invalid JSON"},
+	} {
+		todo, err := parseTodoLine(tc.line, tc.format)
+
+		if tc.err != "" {
+			if assert.Error(t, err, tc.line) {
+				assert.Contains(t, err.Error(), tc.err, tc.line)
+			}
+			continue
+		}
+		assert.NoError(t, err, tc.line)
+		assert.Equal(t, tc.todo, todo, tc.line)
+	}
+}
+
+func TestAddMany(t *testing.T) {
+	notes := `- [ ] Send the minutes
+- [x] Book the room
+
+{"title": "Review the budget", "description": "Before Friday"}
+{"title": ""}
+Call the printer guy
+`
+
+	for _, args := range [][]string{{"add-many"}, {"create", "-"}} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			svc := newMemService()
+			out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+			a := &Application{Service: svc, Out: out, Err: errOut, In: strings.NewReader(notes)}
+
+			err := a.Run(append([]string{"gotodocli"}, args...))
+
+			assert.EqualError(t, err, "1 of 5 todos failed")
+			assert.Equal(t, ExitValidation, ExitCode(err))
+			assert.Contains(t, errOut.String(), "line 5: title must not be empty")
+			assert.Equal(t, 4, strings.Count(out.String(), "Created todo:"))
+
+			var titles []string
+			for _, todo := range svc.GetAll() {
+				titles = append(titles, todo.Title)
+			}
+			assert.Equal(t, []string{"Send the minutes", "Book the room", "Review the budget", "Call the printer guy"}, titles)
+			assert.Equal(t, "Book the room", svc.GetFinished()[0].Title)
+		})
+	}
+}
+
+func TestAddManyMarkdown(t *testing.T) {
+	svc := newMemService()
+	a := &Application{Service: svc, Out: &bytes.Buffer{}, In: strings.NewReader("# Notes\n- [ ] Send the minutes\nWe talked.\n")}
+
+	err := a.Run([]string{"gotodocli", "add-many", "--format", "markdown"})
+
+	assert.NoError(t, err)
+	if todos := svc.GetAll(); assert.Len(t, todos, 1) {
+		assert.Equal(t, "Send the minutes", todos[0].Title)
+	}
+}
+
+func TestAddManyDryRun(t *testing.T) {
+	svc := newMemService()
+	out := &bytes.Buffer{}
+	a := &Application{Service: svc, Out: out, In: strings.NewReader("Buy milk\nBuy eggs\n")}
+
+	err := a.Run([]string{"gotodocli", "add-many", "-n"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(out.String(), "Would create todo:"))
+	assert.Empty(t, svc.GetAll())
+}
@@ -1,22 +1,135 @@
 package cli
 
 import (
+	"errors"
+	"io"
+	"os"
+
 	"github.com/saifulwebid/gotodo"
 	"gopkg.in/urfave/cli.v1"
+
+	"github.com/saifulwebid/gotodoapp/config"
+	"github.com/saifulwebid/gotodoapp/profile"
+	"github.com/saifulwebid/gotodoapp/scan"
+	"github.com/saifulwebid/gotodoapp/stats"
+	"github.com/saifulwebid/gotodoapp/timestamps"
 )
 
 // Application is a wrapper to urfave/cli package. It also contains an instance
-// to gotodo.Service to be used by all CLI commands.
+// to gotodo.Service to be used by all CLI commands, and the configuration the
+// service is set up with.
 type Application struct {
 	Service gotodo.Service
+	Config  *config.Config
+
+	// Out and Err are where commands write their output and errors. They
+	// default to os.Stdout and os.Stderr.
+	Out io.Writer
+	Err io.Writer
+
+	// In is where todos are read from by add-many, and confirmations are
+	// read from. It defaults to os.Stdin, in which case confirmations are
+	// refused unless it is a terminal.
+	In io.Reader
+
+	// ProfilesFile is the path of the profiles file. If empty, profiles are
+	// not used.
+	ProfilesFile string
+
+	// Connect returns the Service of a profile, or the one set up with Config
+	// if the profile is nil. If Service is nil, it is called before running
+	// the first command which needs one.
+	Connect func(p *profile.Profile) (gotodo.Service, error)
+
+	// profile is the profile in use, if any.
+	profile *profile.Profile
+
+	// output is the output format, set by the --output flag or the profile.
+	output string
+
+	// jsonErrors is set by the --json-errors flag.
+	jsonErrors bool
+
+	// inShell is set while commands are run by the shell command.
+	inShell bool
+}
+
+func (a *Application) stdout() io.Writer {
+	if a.Out == nil {
+		return os.Stdout
+	}
+
+	return a.Out
+}
+
+func (a *Application) stderr() io.Writer {
+	if a.Err == nil {
+		return os.Stderr
+	}
+
+	return a.Err
+}
+
+// connect sets Service up, if it is not yet.
+func (a *Application) connect() error {
+	if a.Service != nil {
+		return nil
+	}
+	if a.Connect == nil {
+		return errors.New("no service to connect to")
+	}
+
+	svc, err := a.Connect(a.profile)
+	if err != nil {
+		return storageError(err)
+	}
+	a.Service = svc
+
+	return nil
 }
 
-// Run will set up an urfave/cli.App instance and run it.
+// withService wraps action, so that Service is set up before it runs.
+func (a *Application) withService(action cli.ActionFunc) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if err := a.connect(); err != nil {
+			return err
+		}
+
+		return action(c)
+	}
+}
+
+// Run will set up an urfave/cli.App instance and run it. The error of the
+// command, if any, is reported to Err and returned; ExitCode tells the exit
+// code it maps to.
 func (a *Application) Run(arguments []string) error {
-	doneFlags := []cli.Flag{
+	err := a.newApp().Run(arguments)
+	if err != nil {
+		writeError(a.stderr(), err, a.jsonErrors)
+	}
+
+	return err
+}
+
+// newApp sets up an urfave/cli.App instance running the commands of a.
+func (a *Application) newApp() *cli.App {
+	getAllFlags := []cli.Flag{
 		cli.BoolFlag{
 			Name: "done, d",
 		},
+		cli.StringFlag{
+			Name:  "since",
+			Usage: "get the todos created from `TIME`, a date, an RFC 3339 time or a duration ago such as 7d",
+		},
+		cli.StringFlag{
+			Name:  "until",
+			Usage: "get the todos created until `TIME`, included if it is a date",
+		},
+		cli.StringFlag{
+			Name:  "timestamp",
+			Value: timestamps.FieldCreated,
+			Usage: "filter by the time todos were `created`, updated or completed",
+		},
 	}
 	todoFlags := []cli.Flag{
 		cli.StringFlag{
@@ -25,52 +138,266 @@ func (a *Application) Run(arguments []string) error {
 		cli.StringFlag{
 			Name: "description, desc, d",
 		},
+		cli.BoolFlag{
+			Name:  "editor, e",
+			Usage: "write the todo in $VISUAL or $EDITOR",
+		},
+	}
+
+	matchFlag := cli.StringFlag{
+		Name:  "match, m",
+		Usage: "select the todos whose title or description contains `TEXT`",
+	}
+	yesFlag := cli.BoolFlag{
+		Name:  "yes, y",
+		Usage: "do not ask for confirmation",
+	}
+	dryRunFlag := cli.BoolFlag{
+		Name:  "dry-run, n",
+		Usage: "print what would be changed, without changing it",
+	}
+	formatFlag := cli.StringFlag{
+		Name:  "format, f",
+		Value: formatAuto,
+		Usage: "read todos from stdin as `FORMAT`: auto, lines, markdown or jsonl",
 	}
 
 	app := cli.NewApp()
 
 	app.Name = "gotodocli"
 	app.Usage = "manage your todos"
+	app.Writer = a.stdout()
+	app.ErrWriter = a.stderr()
+	app.Flags = []cli.Flag{
+		cli.BoolFlag{
+			Name:   "json-errors",
+			Usage:  "report errors on stderr as JSON",
+			EnvVar: "GOTODOCLI_JSON_ERRORS",
+		},
+		cli.StringFlag{
+			Name:   "profile, p",
+			Usage:  "use the profile called `NAME` instead of the current one",
+			EnvVar: "GOTODO_PROFILE",
+		},
+		cli.StringFlag{
+			Name:   "output, o",
+			Usage:  "print todos as `FORMAT`, either text or json (default: from the profile, or text)",
+			EnvVar: "GOTODOCLI_OUTPUT",
+		},
+	}
+	app.Before = func(c *cli.Context) error {
+		a.jsonErrors = c.Bool("json-errors")
+
+		// The profile commands work on the profiles file as it is, so that an
+		// invalid profile can be fixed.
+		if a.inShell {
+			if c.IsSet("profile") {
+				return usageError("the profile can not be changed in the shell")
+			}
+		} else if c.Args().First() != "profile" {
+			if err := a.selectProfile(c.String("profile")); err != nil {
+				return err
+			}
+		}
+
+		a.output = c.String("output")
+		if a.output == "" && a.profile != nil {
+			a.output = a.profile.Output
+		}
+		switch a.output {
+		case "":
+			a.output = profile.OutputText
+		case profile.OutputText, profile.OutputJSON:
+		default:
+			return usageError("output %q must be either %s or %s", a.output, profile.OutputText, profile.OutputJSON)
+		}
+
+		return nil
+	}
+	app.Action = func(c *cli.Context) error {
+		if c.NArg() > 0 {
+			return usageError("unknown command %q", c.Args().First())
+		}
+
+		return cli.ShowAppHelp(c)
+	}
 	app.Commands = []cli.Command{
 		{
 			Name:   "getall",
 			Usage:  "get all todos from the database",
-			Flags:  doneFlags,
-			Action: a.getAll,
+			Flags:  getAllFlags,
+			Action: a.withService(a.getAll),
+		},
+		{
+			Name:      "get",
+			Usage:     "get todos from the database",
+			ArgsUsage: "[ID|FIRST-LAST]...",
+			Flags:     []cli.Flag{matchFlag},
+			Action:    a.withService(a.get),
 		},
 		{
-			Name:   "get",
-			Usage:  "get a todo from the database",
-			Action: a.get,
+			Name:      "create",
+			Usage:     "create a todo in the database, or todos read from stdin with -",
+			ArgsUsage: "[-]",
+			Flags:     append(todoFlags, formatFlag, dryRunFlag),
+			Action:    a.withService(a.create),
 		},
 		{
-			Name:   "create",
-			Usage:  "create a todo in the database",
-			Flags:  todoFlags,
-			Action: a.create,
+			Name:   "add-many",
+			Usage:  "create todos read from stdin, one per line",
+			Flags:  []cli.Flag{formatFlag, dryRunFlag},
+			Action: a.withService(a.addMany),
 		},
 		{
 			Name:   "edit",
 			Usage:  "edit a todo in the database",
-			Flags:  todoFlags,
-			Action: a.edit,
+			Flags:  append(todoFlags, dryRunFlag),
+			Action: a.withService(a.edit),
 		},
 		{
-			Name:   "done",
-			Usage:  "mark a todo as done",
-			Action: a.markAsDone,
+			Name:      "done",
+			Usage:     "mark todos as done",
+			ArgsUsage: "[ID|FIRST-LAST]...",
+			Flags:     []cli.Flag{matchFlag, yesFlag, dryRunFlag},
+			Action:    a.withService(a.markAsDone),
 		},
 		{
-			Name:   "delete",
-			Usage:  "delete a todo from the database",
-			Action: a.delete,
+			Name:      "delete",
+			Usage:     "delete todos from the database",
+			ArgsUsage: "[ID|FIRST-LAST]...",
+			Flags:     []cli.Flag{matchFlag, yesFlag, dryRunFlag},
+			Action:    a.withService(a.delete),
 		},
 		{
 			Name:   "delete-finished",
 			Usage:  "delete all finished todos from the database",
-			Action: a.deleteFinished,
+			Flags:  []cli.Flag{yesFlag, dryRunFlag},
+			Action: a.withService(a.deleteFinished),
+		},
+		{
+			Name:      "scan",
+			Usage:     "track the TODO, FIXME and XXX comments of a source tree as todos",
+			ArgsUsage: "[DIR]",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "state",
+					Usage: "track the comments in `FILE`, instead of " + scan.StateFile + " in DIR",
+				},
+				dryRunFlag,
+			},
+			Action: a.withService(a.scanTree),
+		},
+		{
+			Name:  "stats",
+			Usage: "report how many todos are pending and finished, and how many were created and completed",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "by, b",
+					Value: stats.Day,
+					Usage: "count the todos created and completed by `INTERVAL`, either day or week",
+				},
+				cli.IntFlag{
+					Name:  "last, l",
+					Usage: "count the last `N` days or weeks (default: 14 days or 8 weeks)",
+				},
+				cli.IntFlag{
+					Name:  "oldest",
+					Value: stats.DefaultOldest,
+					Usage: "list the `N` oldest pending todos",
+				},
+			},
+			Action: a.withService(a.stats),
+		},
+		{
+			Name:   "tui",
+			Usage:  "browse and manage todos in a full-screen terminal interface",
+			Action: a.withService(a.runTUI),
+		},
+		{
+			Name:  "shell",
+			Usage: "run commands interactively, or from a script piped to stdin",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "history",
+					Usage: "path of the history file (default: ~/.gotodocli_history)",
+				},
+			},
+			Action: a.withService(a.runShell),
+		},
+		{
+			Name:      "completion",
+			Usage:     "print the completion script of a shell",
+			ArgsUsage: "bash|zsh|fish",
+			Action:    a.printCompletionScript,
+		},
+		{
+			Name:            "__complete",
+			Hidden:          true,
+			SkipFlagParsing: true,
+			Action:          a.printCompletions,
+		},
+		{
+			Name:  "profile",
+			Usage: "manage the profiles telling where the todos are",
+			Subcommands: []cli.Command{
+				{
+					Name:   "list",
+					Usage:  "list the profiles, marking the one in use",
+					Action: a.listProfiles,
+				},
+				{
+					Name:      "use",
+					Usage:     "use a profile from now on",
+					ArgsUsage: "NAME",
+					Action:    a.useProfile,
+				},
+				{
+					Name:      "add",
+					Usage:     "add a profile",
+					ArgsUsage: "NAME",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "backend, b",
+							Value: profile.BackendDatabase,
+							Usage: "where the todos are: database, or http for a gotodoserver",
+						},
+						cli.StringFlag{Name: "db-host", Usage: "database host (default: from the configuration)"},
+						cli.IntFlag{Name: "db-port", Usage: "database port (default: from the configuration)"},
+						cli.StringFlag{Name: "db-name", Usage: "database name (default: from the configuration)"},
+						cli.StringFlag{Name: "db-user", Usage: "database user (default: from the configuration)"},
+						cli.StringFlag{Name: "db-pass", Usage: "database password (default: from the configuration)"},
+						cli.StringFlag{Name: "url", Usage: "URL of the gotodoserver, for the http backend"},
+						cli.StringFlag{Name: "token", Usage: "bearer token sent to the gotodoserver"},
+						cli.StringFlag{Name: "output", Usage: "default output format, either text or json"},
+						cli.BoolFlag{Name: "use", Usage: "use the profile from now on"},
+					},
+					Action: a.addProfile,
+				},
+			},
 		},
+		{
+			Name:  "config",
+			Usage: "inspect the configuration",
+			Subcommands: []cli.Command{
+				{
+					Name:   "print",
+					Usage:  "print the effective configuration, with secrets redacted",
+					Action: a.printConfig,
+				},
+			},
+		},
+	}
+
+	onUsageError := func(c *cli.Context, err error, isSubcommand bool) error {
+		return usageError("%v", err)
+	}
+	app.OnUsageError = onUsageError
+	for i := range app.Commands {
+		app.Commands[i].OnUsageError = onUsageError
+		for j := range app.Commands[i].Subcommands {
+			app.Commands[i].Subcommands[j].OnUsageError = onUsageError
+		}
 	}
 
-	return app.Run(arguments)
+	return app
 }
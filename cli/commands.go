@@ -2,29 +2,62 @@ package cli
 
 import (
 	"fmt"
-	"log"
 	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/profile"
+	"github.com/saifulwebid/gotodoapp/timestamps"
 )
 
-func parseIDFromCli(c *cli.Context) int {
+func parseIDFromCli(c *cli.Context) (int, error) {
 	idStr := c.Args().Get(0)
 	if idStr == "" {
-		log.Fatal("ID argument must not be blank")
+		return 0, usageError("ID argument must not be blank")
 	}
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		log.Fatal(err)
+		return 0, usageError("invalid ID %q", idStr)
 	}
 
-	return id
+	return id, nil
+}
+
+// getTodo returns the Todo whose ID is the argument of c.
+func (a *Application) getTodo(c *cli.Context) (*gotodo.Todo, error) {
+	id, err := parseIDFromCli(c)
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := a.Service.Get(id)
+	if err != nil {
+		return nil, getError(id, err)
+	}
+
+	return todo, nil
+}
+
+// validateTitle refuses the titles gotodo.Service would refuse, so that its
+// remaining errors can be reported as storage failures.
+func validateTitle(title string) error {
+	if strings.TrimSpace(title) == "" {
+		return validationError("title must not be empty")
+	}
+
+	return nil
 }
 
 func (a *Application) getAll(c *cli.Context) error {
+	filter, err := timestamps.ParseFilter(c.String("timestamp"), c.String("since"), c.String("until"), time.Now())
+	if err != nil {
+		return usageError("%v", err)
+	}
+
 	var todos []*gotodo.Todo
 
 	if c.IsSet("done") {
@@ -37,46 +70,85 @@ func (a *Application) getAll(c *cli.Context) error {
 		todos = a.Service.GetAll()
 	}
 
-	fmt.Println(todosToString(todos))
+	var times map[int]timestamps.Times
+	if timed, ok := a.Service.(timestamps.Timed); ok {
+		if times, err = timed.Times(); err != nil && !filter.IsZero() {
+			return storageError(err)
+		}
+	} else if !filter.IsZero() {
+		return usageError("--since and --until require the timestamps of todos, which are not recorded")
+	}
+
+	a.printTodosWithTimes("", filter.Apply(todos, times), times)
 
 	return nil
 }
 
 func (a *Application) get(c *cli.Context) error {
-	id := parseIDFromCli(c)
-
-	todo, err := a.Service.Get(id)
+	todos, err := a.selectTodos(c, a.Service.GetAll)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	fmt.Println(todoToString(todo))
+	printed := 0
+	return a.forEach(todos, func(todo *gotodo.Todo) error {
+		if printed > 0 {
+			a.printMessage("----------------------------")
+		}
+		a.printTodo("", todo)
+		printed++
 
-	return nil
+		return nil
+	})
 }
 
 func (a *Application) create(c *cli.Context) error {
-	todo, err := a.Service.Add(c.String("title"), c.String("description"))
+	if c.Args().First() == "-" {
+		return a.addMany(c)
+	}
+
+	todo := &gotodo.Todo{Title: c.String("title"), Description: c.String("description")}
+	if c.Bool("editor") {
+		var err error
+		todo, err = editInEditor(todo)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := validateTitle(todo.Title); err != nil {
+		return err
+	}
+
+	if c.Bool("dry-run") {
+		a.printTodo("Would create todo:", todo)
+		return nil
+	}
+
+	created, err := a.Service.Add(todo.Title, todo.Description)
 	if err != nil {
-		log.Fatal(err)
+		return storageError(err)
 	}
 
-	fmt.Println("Created todo:")
-	fmt.Println(todoToString(todo))
+	if todo.Done {
+		if err := a.Service.MarkAsDone(created); err != nil {
+			return storageError(fmt.Errorf("todo %d: %w", created.ID, err))
+		}
+	}
+
+	a.printTodo("Created todo:", created)
 
 	return nil
 }
 
 func (a *Application) edit(c *cli.Context) error {
-	if c.NumFlags() == 0 {
-		log.Fatal("No --title or --description set; exiting")
+	if !c.IsSet("title") && !c.IsSet("description") && !c.Bool("editor") {
+		return usageError("No --title, --description or --editor set; exiting")
 	}
 
-	id := parseIDFromCli(c)
-
-	todo, err := a.Service.Get(id)
+	todo, err := a.getTodo(c)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	if c.IsSet("title") {
@@ -84,62 +156,135 @@ func (a *Application) edit(c *cli.Context) error {
 	}
 
 	if c.IsSet("description") {
-		todo.Title = c.String("description")
+		todo.Description = c.String("description")
+	}
+
+	markAsDone := false
+	if c.Bool("editor") {
+		edited, err := editInEditor(todo)
+		if err != nil {
+			return err
+		}
+		if todo.Done && !edited.Done {
+			return validationError("todo %d is done, and can not be marked as pending", todo.ID)
+		}
+
+		markAsDone = edited.Done && !todo.Done
+		todo = edited
+	}
+
+	if err := validateTitle(todo.Title); err != nil {
+		return err
+	}
+
+	if c.Bool("dry-run") {
+		a.printTodo("Would edit todo:", todo)
+		return nil
 	}
 
 	err = a.Service.Edit(todo)
 	if err != nil {
-		log.Fatal(err)
+		return storageError(err)
+	}
+
+	if markAsDone {
+		if err := a.Service.MarkAsDone(todo); err != nil {
+			return storageError(fmt.Errorf("todo %d: %w", todo.ID, err))
+		}
 	}
 
-	fmt.Println("Edited todo:")
-	fmt.Println(todoToString(todo))
+	a.printTodo("Edited todo:", todo)
 
 	return nil
 }
 
 func (a *Application) markAsDone(c *cli.Context) error {
-	id := parseIDFromCli(c)
-
-	todo, err := a.Service.Get(id)
+	todos, err := a.selectTodos(c, a.Service.GetPending)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	err = a.Service.MarkAsDone(todo)
-	if err != nil {
-		log.Fatal(err)
+	dryRun := c.Bool("dry-run")
+	if pending := found(todos); !dryRun && len(pending) > a.confirmThreshold() {
+		if err := a.confirm(c, "mark as done", pending); err != nil {
+			return err
+		}
 	}
 
-	fmt.Println("Todo marked as done:")
-	fmt.Println(todoToString(todo))
+	return a.forEach(todos, func(todo *gotodo.Todo) error {
+		if dryRun {
+			a.printTodo("Would mark todo as done:", todo)
+			return nil
+		}
 
-	return nil
+		if err := a.Service.MarkAsDone(todo); err != nil {
+			return storageError(fmt.Errorf("todo %d: %w", todo.ID, err))
+		}
+
+		a.printTodo("Todo marked as done:", todo)
+
+		return nil
+	})
 }
 
 func (a *Application) delete(c *cli.Context) error {
-	id := parseIDFromCli(c)
-
-	todo, err := a.Service.Get(id)
+	todos, err := a.selectTodos(c, a.Service.GetAll)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	err = a.Service.Delete(todo)
-	if err != nil {
-		log.Fatal(err)
+	dryRun := c.Bool("dry-run")
+	if !dryRun {
+		if err := a.confirm(c, "delete", found(todos)); err != nil {
+			return err
+		}
 	}
 
-	fmt.Println("Todo deleted:")
-	fmt.Println(todoToString(todo))
+	return a.forEach(todos, func(todo *gotodo.Todo) error {
+		if dryRun {
+			a.printTodo("Would delete todo:", todo)
+			return nil
+		}
+
+		if err := a.Service.Delete(todo); err != nil {
+			return storageError(fmt.Errorf("todo %d: %w", todo.ID, err))
+		}
 
-	return nil
+		a.printTodo("Todo deleted:", todo)
+
+		return nil
+	})
 }
 
 func (a *Application) deleteFinished(c *cli.Context) error {
+	finished := a.Service.GetFinished()
+	if len(finished) == 0 {
+		a.printMessage("no finished todo to delete")
+		if a.output == profile.OutputJSON {
+			a.printTodos("", finished)
+		}
+		return nil
+	}
+
+	if c.Bool("dry-run") {
+		a.printTodos("Would delete finished todos:", finished)
+		return nil
+	}
+
+	if err := a.confirm(c, "delete", finished); err != nil {
+		return err
+	}
+
 	a.Service.DeleteFinished()
 
-	fmt.Println("all finished todo are deleted")
+	a.printMessage("all finished todo are deleted")
+	if a.output == profile.OutputJSON {
+		a.printTodos("", finished)
+	}
 
 	return nil
 }
+
+func (a *Application) printConfig(c *cli.Context) error {
+	return a.Config.Print(a.stdout())
+}
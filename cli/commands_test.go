@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// run runs gotodocli with args on svc, and returns its exit code, stdout and
+// stderr.
+func run(svc *memService, args ...string) (int, string, string) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	a := &Application{Service: svc, Out: out, Err: errOut}
+
+	err := a.Run(append([]string{"gotodocli"}, args...))
+
+	return ExitCode(err), out.String(), errOut.String()
+}
+
+func TestCommands(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		args   []string
+		failed bool
+		code   int
+		out    string
+		errOut string
+	}{
+		{"getall", []string{"getall"}, false, 0, "Title: Buy milk", ""},
+		{"getall finished", []string{"getall", "--done"}, false, 0, "Title: Buy eggs", ""},
+		{"get", []string{"get", "2"}, false, 0, "Done: Finished", ""},
+		{"create", []string{"create", "-t", "Walk the dog"}, false, 0, "ID: 3", ""},
+		{"edit", []string{"edit", "1", "--desc", "Three bottles"}, false, 0, "Description: Three bottles", ""},
+		{"done", []string{"done", "1"}, false, 0, "Todo marked as done:", ""},
+		{"delete", []string{"delete", "--yes", "1"}, false, 0, "Todo deleted:", ""},
+		{"delete-finished", []string{"delete-finished", "-y"}, false, 0, "all finished todo are deleted", ""},
+
+		{"missing ID", []string{"get"}, false, ExitUsage, "", "ID argument must not be blank"},
+		{"invalid ID", []string{"done", "one"}, false, ExitUsage, "", `invalid ID "one"`},
+		{"unknown flag", []string{"getall", "--pending"}, false, ExitUsage, "", "flag provided but not defined"},
+		{"unknown command", []string{"finish", "1"}, false, ExitUsage, "", `unknown command "finish"`},
+		{"edit without changes", []string{"edit", "1"}, false, ExitUsage, "", "No --title, --description or --editor set"},
+		{"not found", []string{"delete", "42"}, false, ExitNotFound, "", "todo 42 not found"},
+		{"empty title", []string{"create", "--desc", "No title"}, false, ExitValidation, "", "title must not be empty"},
+		{"blank title", []string{"edit", "1", "-t", " "}, false, ExitValidation, "", "title must not be empty"},
+		{"storage failure", []string{"get", "1"}, true, ExitStorage, "", "connection refused"},
+		{"storage failure on create", []string{"create", "-t", "Walk the dog"}, true, ExitStorage, "", "connection refused"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := newMemService("Buy milk", "Buy eggs")
+			svc.MarkAsDone(svc.GetAll()[1])
+			if tc.failed {
+				svc.failure = errors.New("dial tcp: connection refused")
+			}
+
+			code, out, errOut := run(svc, tc.args...)
+
+			assert.Equal(t, tc.code, code)
+			assert.Contains(t, out, tc.out)
+			assert.Contains(t, errOut, tc.errOut)
+			if tc.code == 0 {
+				assert.Empty(t, errOut)
+			}
+		})
+	}
+}
+
+func TestEditKeepsTitle(t *testing.T) {
+	svc := newMemService("Buy milk")
+
+	code, _, _ := run(svc, "edit", "1", "--description", "Two bottles")
+
+	assert.Equal(t, 0, code)
+	todo, _ := svc.Get(1)
+	assert.Equal(t, "Buy milk", todo.Title)
+	assert.Equal(t, "Two bottles", todo.Description)
+}
+
+func TestJSONErrors(t *testing.T) {
+	code, _, errOut := run(newMemService(), "--json-errors", "get", "42")
+
+	assert.Equal(t, ExitNotFound, code)
+
+	var report struct {
+		Error struct {
+			Code    int
+			Kind    string
+			Message string
+		}
+	}
+	if assert.NoError(t, json.Unmarshal([]byte(errOut), &report)) {
+		assert.Equal(t, ExitNotFound, report.Error.Code)
+		assert.Equal(t, "not_found", report.Error.Kind)
+		assert.Equal(t, "todo 42 not found", report.Error.Message)
+	}
+}
@@ -0,0 +1,260 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/saifulwebid/gotodoapp/profile"
+)
+
+// idCommands are the commands taking Todo IDs as their arguments, mapped to
+// whether they take several.
+var idCommands = map[string]bool{"get": true, "edit": false, "done": true, "delete": true}
+
+// completionScripts are the scripts printed by the completion command, by
+// shell. They complete command lines by running the hidden __complete
+// command, which prints a completion and its description per line, separated
+// by a tab.
+var completionScripts = map[string]string{
+	"bash": `# bash completion for gotodocli
+_gotodocli() {
+    local cur=${COMP_WORDS[COMP_CWORD]}
+    local IFS=$'\n'
+    COMPREPLY=($(gotodocli __complete "${COMP_WORDS[@]:1:COMP_CWORD-1}" "$cur" 2>/dev/null))
+
+    # Show the descriptions when there is a choice, and complete the word
+    # alone otherwise.
+    local i
+    for i in "${!COMPREPLY[@]}"; do
+        if [[ ${#COMPREPLY[@]} -eq 1 ]]; then
+            COMPREPLY[i]=${COMPREPLY[i]%%$'\t'*}
+        else
+            COMPREPLY[i]=${COMPREPLY[i]/$'\t'/  -- }
+        fi
+    done
+}
+complete -F _gotodocli gotodocli
+`,
+	"zsh": `#compdef gotodocli
+# zsh completion for gotodocli
+_gotodocli() {
+    local -a candidates
+    local line
+    for line in "${(@f)$(gotodocli __complete "${(@)words[2,CURRENT-1]}" "${words[CURRENT]}" 2>/dev/null)}"; do
+        [[ -n $line ]] && candidates+=("${line%%$'\t'*}:${line#*$'\t'}")
+    done
+    _describe -V gotodocli candidates
+}
+compdef _gotodocli gotodocli
+`,
+	"fish": `# fish completion for gotodocli
+function __gotodocli_complete
+    set -l words (commandline -opc)
+    gotodocli __complete $words[2..-1] (commandline -ct) 2>/dev/null
+end
+complete -c gotodocli -f -a '(__gotodocli_complete)'
+`,
+}
+
+// shells returns the names of the shells having a completion script, sorted.
+func shells() []string {
+	var names []string
+	for name := range completionScripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// completion is a word completing a command line, and what it stands for.
+type completion struct {
+	word        string
+	description string
+}
+
+// globalValueFlags maps the names of the global flags taking a value to their
+// long name.
+var globalValueFlags = map[string]string{"profile": "profile", "p": "profile", "output": "output", "o": "output"}
+
+// completions returns what may complete word, the word being typed after
+// words on a command line of commands: command names, flags, the values of
+// the global flags, or the IDs of the Todos for the commands taking some.
+func (a *Application) completions(commands []cli.Command, words []string, word string) []completion {
+	// Skip the global flags, and their values.
+	var profileName, flagOfWord string
+	for len(words) > 0 && strings.HasPrefix(words[0], "-") {
+		name := strings.TrimLeft(words[0], "-")
+		words = words[1:]
+
+		value := ""
+		if i := strings.Index(name, "="); i >= 0 {
+			name, value = name[:i], name[i+1:]
+		} else if globalValueFlags[name] != "" {
+			if len(words) == 0 {
+				flagOfWord = globalValueFlags[name]
+				break
+			}
+			value, words = words[0], words[1:]
+		}
+
+		if globalValueFlags[name] == "profile" {
+			profileName = value
+		}
+	}
+
+	var candidates []completion
+	if flagOfWord == "profile" {
+		candidates = a.profileCompletions()
+	} else if flagOfWord == "output" {
+		candidates = []completion{{word: profile.OutputText}, {word: profile.OutputJSON}}
+	} else if len(words) == 0 {
+		for _, command := range commands {
+			if !command.Hidden {
+				candidates = append(candidates, completion{command.Name, command.Usage})
+			}
+		}
+	} else if command := findCommand(commands, words[0]); command != nil {
+		several, takesIDs := idCommands[command.Name]
+
+		switch {
+		case strings.HasPrefix(word, "-"):
+			for _, flag := range command.Flags {
+				name := strings.Split(flag.GetName(), ",")[0]
+				candidates = append(candidates, completion{"--" + name, flagUsage(flag)})
+			}
+		case len(command.Subcommands) > 0 && len(words) == 1:
+			for _, sub := range command.Subcommands {
+				candidates = append(candidates, completion{sub.Name, sub.Usage})
+			}
+		case command.Name == "completion" && len(words) == 1:
+			for _, shell := range shells() {
+				candidates = append(candidates, completion{word: shell})
+			}
+		case takesIDs && (several || len(words) == 1):
+			candidates = a.idCompletions(profileName, command.Name, words[1:])
+		}
+	}
+
+	var ret []completion
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate.word, word) {
+			ret = append(ret, candidate)
+		}
+	}
+
+	return ret
+}
+
+// idCompletions returns the IDs of the Todos command may take, with their
+// titles, except those already given. The Todos are those of the profile
+// called profileName, if it is set.
+func (a *Application) idCompletions(profileName, command string, given []string) []completion {
+	if profileName != "" && a.Service == nil && !a.inShell {
+		if a.selectProfile(profileName) != nil {
+			return nil
+		}
+	}
+	if a.connect() != nil {
+		return nil
+	}
+
+	todos := a.Service.GetAll()
+	if command == "done" {
+		todos = a.Service.GetPending()
+	}
+
+	skip := map[string]bool{}
+	for _, word := range given {
+		skip[word] = true
+	}
+
+	var candidates []completion
+	for _, todo := range todos {
+		if id := strconv.Itoa(todo.ID); !skip[id] {
+			candidates = append(candidates, completion{id, todo.Title})
+		}
+	}
+
+	return candidates
+}
+
+// profileCompletions returns the names of the profiles, with their target.
+func (a *Application) profileCompletions() []completion {
+	if a.ProfilesFile == "" {
+		return nil
+	}
+	f, err := a.profiles()
+	if err != nil {
+		return nil
+	}
+
+	var candidates []completion
+	for _, name := range f.Names() {
+		candidates = append(candidates, completion{name, profileTarget(f.Profiles[name])})
+	}
+
+	return candidates
+}
+
+func findCommand(commands []cli.Command, name string) *cli.Command {
+	for i := range commands {
+		if commands[i].HasName(name) {
+			return &commands[i]
+		}
+	}
+
+	return nil
+}
+
+// flagUsage returns the usage of flag, without the backquotes marking its
+// placeholder.
+func flagUsage(flag cli.Flag) string {
+	usage := ""
+	switch f := flag.(type) {
+	case cli.BoolFlag:
+		usage = f.Usage
+	case cli.StringFlag:
+		usage = f.Usage
+	case cli.IntFlag:
+		usage = f.Usage
+	}
+
+	return strings.Replace(usage, "`", "", -1)
+}
+
+// printCompletionScript prints the completion script of the shell named by
+// the argument of c.
+func (a *Application) printCompletionScript(c *cli.Context) error {
+	shell := c.Args().First()
+	script, ok := completionScripts[shell]
+	if !ok {
+		return usageError("unknown shell %q; choose one of %s", shell, strings.Join(shells(), ", "))
+	}
+
+	fmt.Fprint(a.stdout(), script)
+
+	return nil
+}
+
+// printCompletions prints the completions of the command line given as the
+// arguments of c, the last one being the word to complete, one per line with
+// their description after a tab. It is run by the completion scripts.
+func (a *Application) printCompletions(c *cli.Context) error {
+	words := []string(c.Args())
+	if len(words) == 0 {
+		words = []string{""}
+	}
+
+	commands := a.newApp().Commands
+	for _, candidate := range a.completions(commands, words[:len(words)-1], words[len(words)-1]) {
+		description := strings.Replace(candidate.description, "\n", " ", -1)
+		fmt.Fprintf(a.stdout(), "%s\t%s\n", candidate.word, description)
+	}
+
+	return nil
+}
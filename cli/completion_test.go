@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintCompletions(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		words []string
+		out   string
+	}{
+		{"commands", []string{"del"}, "delete\tdelete todos from the database\ndelete-finished\tdelete all finished todos from the database\n"},
+		{"hidden command", []string{"__"}, ""},
+		{"after global flags", []string{"--json-errors", "getal"}, "getall\tget all todos from the database\n"},
+		{"after global flag values", []string{"-o", "json", "--json-errors", "get", "1", ""}, "2\tBuy eggs\n3\tWalk the dog\n"},
+		{"global flag values", []string{"--output", ""}, "text\t\njson\t\n"},
+		{"flags", []string{"delete", "--m"}, "--match\tselect the todos whose title or description contains TEXT\n"},
+		{"IDs", []string{"get", ""}, "1\tBuy milk\n2\tBuy eggs\n3\tWalk the dog\n"},
+		{"pending IDs", []string{"done", ""}, "1\tBuy milk\n3\tWalk the dog\n"},
+		{"more IDs", []string{"delete", "2", ""}, "1\tBuy milk\n3\tWalk the dog\n"},
+		{"a single ID", []string{"edit", "2", ""}, ""},
+		{"subcommands", []string{"config", ""}, "print\tprint the effective configuration, with secrets redacted\n"},
+		{"shells", []string{"completion", "f"}, "fish\t\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := newMemService("Buy milk", "Buy eggs", "Walk the dog")
+			svc.MarkAsDone(svc.GetAll()[1])
+
+			code, out, _ := run(svc, append([]string{"__complete"}, tc.words...)...)
+
+			assert.Equal(t, 0, code)
+			assert.Equal(t, tc.out, out)
+		})
+	}
+}
+
+func TestPrintCompletionScript(t *testing.T) {
+	for shell, line := range map[string]string{
+		"bash": "complete -F _gotodocli gotodocli",
+		"zsh":  "compdef _gotodocli gotodocli",
+		"fish": "complete -c gotodocli -f -a '(__gotodocli_complete)'",
+	} {
+		code, out, _ := run(newMemService(), "completion", shell)
+
+		assert.Equal(t, 0, code, shell)
+		assert.Contains(t, out, line, shell)
+	}
+
+	code, _, errOut := run(newMemService(), "completion", "tcsh")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, errOut, `unknown shell "tcsh"; choose one of bash, fish, zsh`)
+}
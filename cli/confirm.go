@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+// DefaultConfirmThreshold is how many Todos done marks without asking for
+// confirmation, unless configured otherwise. delete always asks.
+const DefaultConfirmThreshold = 5
+
+// errCancelled is returned when the user does not confirm a command.
+var errCancelled = errors.New("cancelled")
+
+func (a *Application) confirmThreshold() int {
+	if a.Config == nil {
+		return DefaultConfirmThreshold
+	}
+
+	return a.Config.CLIConfirmThreshold
+}
+
+// found returns the selected Todos which could be got.
+func found(todos []selected) []*gotodo.Todo {
+	var ret []*gotodo.Todo
+	for _, s := range todos {
+		if s.err == nil {
+			ret = append(ret, s.todo)
+		}
+	}
+
+	return ret
+}
+
+// confirm lists todos and asks the user to confirm verbing them, unless the
+// --yes flag is set. It returns an error unless the user confirms. The answer
+// is read from In, or from stdin if it is a terminal; otherwise, the command
+// is refused.
+func (a *Application) confirm(c *cli.Context, verb string, todos []*gotodo.Todo) error {
+	if len(todos) == 0 || c.Bool("yes") {
+		return nil
+	}
+
+	in := a.In
+	if in == nil {
+		if !stdinIsTerminal() {
+			return usageError("refusing to %s %d todos without confirmation, as stdin is not a terminal; use --yes", verb, len(todos))
+		}
+		in = os.Stdin
+	}
+
+	fmt.Fprintf(a.stderr(), "About to %s these todos:\n", verb)
+	for _, todo := range todos {
+		fmt.Fprintf(a.stderr(), "%6d  %s\n", todo.ID, todo.Title)
+	}
+	fmt.Fprint(a.stderr(), "Continue? [y/N] ")
+
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+
+	return errCancelled
+}
+
+// stdinIsTerminal tells whether os.Stdin is a terminal, rather than a pipe or
+// a file.
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirmation(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		args      []string
+		answer    string
+		confirmed bool
+		asked     bool
+	}{
+		{"confirmed", []string{"delete", "1-2"}, "y\n", true, true},
+		{"refused", []string{"delete", "1"}, "n\n", false, true},
+		{"no answer", []string{"delete-finished"}, "", false, true},
+		{"yes flag", []string{"delete", "--yes", "1-6"}, "", true, false},
+		{"done under threshold", []string{"done", "1-5"}, "", true, false},
+		{"done over threshold", []string{"done", "1-6"}, "yes\n", true, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := newMemService("1", "2", "3", "4", "5", "6")
+			svc.MarkAsDone(svc.GetAll()[0])
+			out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+			a := &Application{Service: svc, Out: out, Err: errOut, In: strings.NewReader(tc.answer)}
+
+			err := a.Run(append([]string{"gotodocli"}, tc.args...))
+
+			if tc.confirmed {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, out.String())
+			} else {
+				assert.Equal(t, errCancelled, err)
+				assert.Empty(t, out.String())
+				assert.Len(t, svc.GetAll(), 6)
+			}
+			assert.Equal(t, tc.asked, strings.Contains(errOut.String(), "Continue? [y/N]"))
+		})
+	}
+}
+
+func TestConfirmationListsTodos(t *testing.T) {
+	svc := newMemService("Buy milk", "Buy eggs")
+	errOut := &bytes.Buffer{}
+	a := &Application{Service: svc, Out: &bytes.Buffer{}, Err: errOut, In: strings.NewReader("\n")}
+
+	a.Run([]string{"gotodocli", "delete", "1-2"})
+
+	assert.Equal(t, "About to delete these todos:\n"+
+		"     1  Buy milk\n"+
+		"     2  Buy eggs\n"+
+		"Continue? [y/N] gotodocli: cancelled\n", errOut.String())
+}
+
+func TestConfirmationRefusedWithoutTerminal(t *testing.T) {
+	if stdinIsTerminal() {
+		t.Skip("stdin is a terminal")
+	}
+
+	svc := newMemService("Buy milk")
+
+	code, _, errOut := run(svc, "delete", "1")
+
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, errOut, "stdin is not a terminal; use --yes")
+	assert.Len(t, svc.GetAll(), 1)
+}
+
+func TestDryRun(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+		out  string
+	}{
+		{"delete", []string{"delete", "-n", "1-2"}, "Would delete todo:\nID: 1"},
+		{"delete-finished", []string{"delete-finished", "--dry-run"}, "Would delete finished todos:\nID: 1"},
+		{"edit", []string{"edit", "2", "--dry-run", "-t", "Buy bread"}, "Would edit todo:\nID: 2\nTitle: Buy bread"},
+		{"done", []string{"done", "--dry-run", "--match", "eggs"}, "Would mark todo as done:\nID: 2"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := newMemService("Buy milk", "Buy eggs")
+			svc.MarkAsDone(svc.GetAll()[0])
+			before := todosToString(svc.GetAll(), nil)
+
+			code, out, errOut := run(svc, tc.args...)
+
+			assert.Equal(t, 0, code)
+			assert.Contains(t, out, tc.out)
+			assert.Empty(t, errOut)
+			assert.Equal(t, before, todosToString(svc.GetAll(), nil))
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+// defaultEditor is run when neither VISUAL nor EDITOR is set.
+const defaultEditor = "vi"
+
+// editorCommand returns the command line of the user's editor.
+func editorCommand() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(name); editor != "" {
+			return editor
+		}
+	}
+
+	return defaultEditor
+}
+
+// runEditor runs the user's editor on path, attached to the terminal.
+func runEditor(path string) error {
+	args, err := splitArgs(editorCommand())
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New("no editor set")
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	return cmd.Run()
+}
+
+// editInEditor writes todo to a temporary file, lets the user edit it in
+// their editor, and returns the Todo as saved. It fails if the file is saved
+// unchanged or empty.
+func editInEditor(todo *gotodo.Todo) (*gotodo.Todo, error) {
+	f, err := ioutil.TempFile("", "gotodo-*.md")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+
+	content := formatTodoFile(todo)
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runEditor(f.Name()); err != nil {
+		return nil, fmt.Errorf("cannot run editor: %w", err)
+	}
+
+	edited, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(edited)) == 0 {
+		return nil, errors.New("aborted: the todo file is empty")
+	}
+	if bytes.Equal(edited, content) {
+		return nil, errors.New("aborted: the todo file is unchanged")
+	}
+
+	saved, err := parseTodoFile(edited)
+	if err != nil {
+		return nil, err
+	}
+	saved.ID = todo.ID
+
+	return saved, nil
+}
+
+// formatTodoFile writes todo as a front matter, holding its title and whether
+// it is done, followed by its description.
+func formatTodoFile(todo *gotodo.Todo) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintln(&b, "---")
+	fmt.Fprintln(&b, "title:", todo.Title)
+	fmt.Fprintln(&b, "done:", todo.Done)
+	fmt.Fprintln(&b, "---")
+	if todo.Description != "" {
+		fmt.Fprintln(&b, todo.Description)
+	}
+
+	return b.Bytes()
+}
+
+// parseTodoFile reads a Todo written by formatTodoFile, then edited.
+func parseTodoFile(content []byte) (*gotodo.Todo, error) {
+	lines := strings.Split(strings.Replace(string(content), "\r\n", "\n", -1), "\n")
+	if strings.TrimSpace(lines[0]) != "---" {
+		return nil, validationError("the todo file must start with a --- line")
+	}
+
+	end := 0
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
+			break
+		}
+	}
+	if end == 0 {
+		return nil, validationError("the front matter of the todo file must end with a --- line")
+	}
+
+	todo := &gotodo.Todo{}
+	for i, line := range lines[1:end] {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			return nil, validationError("line %d of the todo file must be written as key: value", i+2)
+		}
+		key, value := strings.TrimSpace(line[:colon]), strings.TrimSpace(line[colon+1:])
+
+		switch strings.ToLower(key) {
+		case "title":
+			todo.Title = value
+		case "done":
+			done, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, validationError("done must be true or false, not %q", value)
+			}
+			todo.Done = done
+		default:
+			return nil, validationError("unknown key %q in the todo file", key)
+		}
+	}
+
+	body := strings.Join(lines[end+1:], "\n")
+	todo.Description = strings.TrimRight(strings.TrimLeft(body, "\n"), " \t\n")
+
+	return todo, nil
+}
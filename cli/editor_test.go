@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+func TestTodoFile(t *testing.T) {
+	todo := &gotodo.Todo{Title: "Buy milk", Description: "Two bottles\n\nSkimmed", Done: true}
+
+	content := formatTodoFile(todo)
+	assert.Equal(t, "---\ntitle: Buy milk\ndone: true\n---\nTwo bottles\n\nSkimmed\n", string(content))
+
+	parsed, err := parseTodoFile(content)
+	assert.NoError(t, err)
+	assert.Equal(t, todo, parsed)
+
+	for _, tc := range []struct {
+		content string
+		err     string
+	}{
+		{"title: Buy milk\n", "must start with a --- line"},
+		{"---\ntitle: Buy milk\n", "must end with a --- line"},
+		{"---\ntitle Buy milk\n---\n", "line 2 of the todo file must be written as key: value"},
+		{"---\ndone: maybe\n---\n", `done must be true or false, not "maybe"`},
+		{"---\ndue: tomorrow\n---\n", `unknown key "due"`},
+	} {
+		_, err := parseTodoFile([]byte(tc.content))
+		if assert.Error(t, err, tc.content) {
+			assert.Contains(t, err.Error(), tc.err)
+			assert.Equal(t, ExitValidation, ExitCode(err))
+		}
+	}
+}
+
+// useEditor makes the editor replace the file it edits with content, or leave
+// it as it is if content is nil.
+func useEditor(t *testing.T, content []byte) {
+	dir := t.TempDir()
+	script := "#!/bin/sh\n"
+	if content != nil {
+		saved := filepath.Join(dir, "saved")
+		if err := ioutil.WriteFile(saved, content, 0600); err != nil {
+			t.Fatal(err)
+		}
+		script += "cp '" + saved + "' \"$1\"\n"
+	}
+
+	editor := filepath.Join(dir, "editor")
+	if err := ioutil.WriteFile(editor, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", editor)
+}
+
+func TestEditor(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		svc := newMemService()
+		useEditor(t, []byte("---\ntitle: Buy milk\ndone: false\n---\n\nTwo bottles\nSkimmed\n\n"))
+
+		code, out, errOut := run(svc, "create", "-e")
+
+		assert.Equal(t, 0, code, errOut)
+		assert.Contains(t, out, "Created todo:")
+		todo, _ := svc.Get(1)
+		assert.Equal(t, &gotodo.Todo{ID: 1, Title: "Buy milk", Description: "Two bottles\nSkimmed"}, todo)
+	})
+
+	t.Run("edit", func(t *testing.T) {
+		svc := newMemService("Buy milk")
+		useEditor(t, []byte("---\ntitle: Buy eggs\ndone: true\n---\nA dozen\n"))
+
+		code, _, errOut := run(svc, "edit", "-e", "1")
+
+		assert.Equal(t, 0, code, errOut)
+		todo, _ := svc.Get(1)
+		assert.Equal(t, &gotodo.Todo{ID: 1, Title: "Buy eggs", Description: "A dozen", Done: true}, todo)
+	})
+
+	for _, tc := range []struct {
+		name    string
+		args    []string
+		content []byte
+		code    int
+		errOut  string
+	}{
+		{"unchanged", []string{"edit", "1", "--editor"}, nil, ExitFailure, "aborted: the todo file is unchanged"},
+		{"empty", []string{"create", "--editor"}, []byte("\n"), ExitFailure, "aborted: the todo file is empty"},
+		{"empty title", []string{"create", "-e"}, []byte("---\ntitle:\n---\nTwo bottles\n"), ExitValidation, "title must not be empty"},
+		{"pending again", []string{"edit", "-e", "2"}, []byte("---\ntitle: Buy eggs\ndone: false\n---\n"), ExitValidation, "todo 2 is done"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := newMemService("Buy milk", "Buy eggs")
+			svc.MarkAsDone(svc.GetAll()[1])
+			before := todosToString(svc.GetAll(), nil)
+			useEditor(t, tc.content)
+
+			code, _, errOut := run(svc, tc.args...)
+
+			assert.Equal(t, tc.code, code)
+			assert.Contains(t, errOut, tc.errOut)
+			assert.Equal(t, before, todosToString(svc.GetAll(), nil))
+		})
+	}
+}
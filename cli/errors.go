@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Exit codes of gotodocli.
+const (
+	// ExitFailure is returned for failures which fit no other code.
+	ExitFailure = 1
+	// ExitUsage is returned when the command line is invalid.
+	ExitUsage = 2
+	// ExitNotFound is returned when a Todo does not exist.
+	ExitNotFound = 3
+	// ExitValidation is returned when a Todo is refused, e.g. without title.
+	ExitValidation = 4
+	// ExitStorage is returned when the storage behind gotodo.Service fails.
+	ExitStorage = 5
+)
+
+var errorKinds = map[int]string{
+	ExitFailure:    "failure",
+	ExitUsage:      "usage",
+	ExitNotFound:   "not_found",
+	ExitValidation: "validation",
+	ExitStorage:    "storage",
+}
+
+// Error is an error of a gotodocli command, along with the exit code it maps
+// to.
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Kind names the class of e, e.g. "not_found".
+func (e *Error) Kind() string {
+	return errorKinds[e.Code]
+}
+
+func usageError(format string, args ...interface{}) error {
+	return &Error{Code: ExitUsage, Err: fmt.Errorf(format, args...)}
+}
+
+func validationError(format string, args ...interface{}) error {
+	return &Error{Code: ExitValidation, Err: fmt.Errorf(format, args...)}
+}
+
+func notFoundError(format string, args ...interface{}) error {
+	return &Error{Code: ExitNotFound, Err: fmt.Errorf(format, args...)}
+}
+
+func storageError(err error) error {
+	return &Error{Code: ExitStorage, Err: err}
+}
+
+// getError classifies an error of gotodo.Service.Get for Todo id. The service
+// does not tell a missing Todo from a storage failure other than by message.
+func getError(id int, err error) error {
+	if strings.Contains(strings.ToLower(err.Error()), "not found") {
+		return notFoundError("todo %d not found", id)
+	}
+
+	return storageError(fmt.Errorf("todo %d: %w", id, err))
+}
+
+// ExitCode returns the exit code gotodocli exits with for err.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	return ExitFailure
+}
+
+// writeError reports err to w, as a human message or as JSON.
+func writeError(w io.Writer, err error, asJSON bool) {
+	if !asJSON {
+		fmt.Fprintln(w, "gotodocli:", err)
+		return
+	}
+
+	code := ExitCode(err)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"kind":    errorKinds[code],
+			"message": err.Error(),
+		},
+	})
+}
@@ -1,12 +1,19 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/profile"
+	"github.com/saifulwebid/gotodoapp/timestamps"
 )
 
-func todoToString(todo *gotodo.Todo) string {
+// timeFormat is how the timestamps of todos are printed, in local time.
+const timeFormat = "2006-01-02 15:04"
+
+func todoToString(todo *gotodo.Todo, times timestamps.Times) string {
 	template := `ID: %d
 Title: %s
 Description: %s
@@ -18,18 +25,104 @@ Done: %s
 		done = "Finished"
 	}
 
-	return fmt.Sprintf(template, todo.ID, todo.Title, todo.Description, done)
+	ret := fmt.Sprintf(template, todo.ID, todo.Title, todo.Description, done)
+	for _, t := range []struct {
+		name string
+		at   time.Time
+	}{
+		{"Created", times.CreatedAt},
+		{"Updated", times.UpdatedAt},
+		{"Completed", times.CompletedAt},
+	} {
+		if !t.at.IsZero() {
+			ret += fmt.Sprintf("%s: %s\n", t.name, t.at.Local().Format(timeFormat))
+		}
+	}
+
+	return ret
 }
 
-func todosToString(todos []*gotodo.Todo) string {
+func todosToString(todos []*gotodo.Todo, times map[int]timestamps.Times) string {
 	ret := ""
 
 	for i, todo := range todos {
 		if i > 0 {
 			ret += "----------------------------\n"
 		}
-		ret += todoToString(todo)
+		ret += todoToString(todo, times[todo.ID])
 	}
 
 	return ret
 }
+
+// times returns the Times of the todos identified by ids, or of every todo if
+// ids is empty, if Service records them. As the Times are only printed along
+// with todos, they are left out if they can not be read.
+func (a *Application) times(ids ...int) map[int]timestamps.Times {
+	timed, ok := a.Service.(timestamps.Timed)
+	if !ok {
+		return nil
+	}
+
+	times, err := timed.Times(ids...)
+	if err != nil {
+		return nil
+	}
+
+	return times
+}
+
+// printTodo prints todo under heading, or as a line of JSON with the JSON
+// output format.
+func (a *Application) printTodo(heading string, todo *gotodo.Todo) {
+	var times timestamps.Times
+	if todo.ID != 0 {
+		times = a.times(todo.ID)[todo.ID]
+	}
+
+	if a.output == profile.OutputJSON {
+		json.NewEncoder(a.stdout()).Encode(timestamps.WithTimes(todo, times))
+		return
+	}
+
+	if heading != "" {
+		fmt.Fprintln(a.stdout(), heading)
+	}
+	fmt.Fprintln(a.stdout(), todoToString(todo, times))
+}
+
+// printTodos prints todos under heading, or as a JSON array with the JSON
+// output format.
+func (a *Application) printTodos(heading string, todos []*gotodo.Todo) {
+	var times map[int]timestamps.Times
+	if len(todos) > 0 {
+		times = a.times()
+	}
+
+	a.printTodosWithTimes(heading, todos, times)
+}
+
+// printTodosWithTimes prints todos as printTodos does, of which times are the
+// Times.
+func (a *Application) printTodosWithTimes(heading string, todos []*gotodo.Todo, times map[int]timestamps.Times) {
+	if a.output == profile.OutputJSON {
+		timed := make([]*timestamps.Todo, len(todos))
+		for i, todo := range todos {
+			timed[i] = timestamps.WithTimes(todo, times[todo.ID])
+		}
+		json.NewEncoder(a.stdout()).Encode(timed)
+		return
+	}
+
+	if heading != "" {
+		fmt.Fprintln(a.stdout(), heading)
+	}
+	fmt.Fprintln(a.stdout(), todosToString(todos, times))
+}
+
+// printMessage prints message, unless the output format is JSON.
+func (a *Application) printMessage(message string) {
+	if a.output != profile.OutputJSON {
+		fmt.Fprintln(a.stdout(), message)
+	}
+}
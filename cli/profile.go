@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/saifulwebid/gotodoapp/profile"
+)
+
+// profiles loads the profiles file.
+func (a *Application) profiles() (*profile.File, error) {
+	if a.ProfilesFile == "" {
+		return nil, usageError("profiles are not available")
+	}
+
+	f, err := profile.Load(a.ProfilesFile)
+	if err != nil {
+		return nil, usageError("%v", err)
+	}
+
+	return f, nil
+}
+
+// selectProfile selects the profile called name, or the current one of the
+// profiles file if name is empty.
+func (a *Application) selectProfile(name string) error {
+	a.profile = nil
+	if a.ProfilesFile == "" {
+		if name != "" {
+			return usageError("profiles are not available")
+		}
+		return nil
+	}
+
+	f, err := a.profiles()
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = f.Current
+	}
+	if name == "" {
+		return nil
+	}
+
+	p, err := f.Get(name)
+	if err != nil {
+		return usageError("%v", err)
+	}
+	a.profile = p
+
+	return nil
+}
+
+// profileTarget describes where the Todos of p are.
+func profileTarget(p *profile.Profile) string {
+	if p.Backend == profile.BackendHTTP {
+		return p.URL
+	}
+
+	if p.DB.Host == "" && p.DB.Port == 0 && p.DB.Name == "" {
+		return "(from the configuration)"
+	}
+
+	target := p.DB.Host
+	if p.DB.Port != 0 {
+		target += ":" + strconv.Itoa(p.DB.Port)
+	}
+	if p.DB.Name != "" {
+		target += "/" + p.DB.Name
+	}
+	if p.DB.User != "" {
+		target = p.DB.User + "@" + target
+	}
+
+	return target
+}
+
+func (a *Application) listProfiles(c *cli.Context) error {
+	f, err := a.profiles()
+	if err != nil {
+		return err
+	}
+
+	if len(f.Profiles) == 0 {
+		fmt.Fprintln(a.stdout(), `No profile; add one with "gotodocli profile add".`)
+		return nil
+	}
+
+	inUse := c.GlobalString("profile")
+	if inUse == "" {
+		inUse = f.Current
+	}
+
+	w := tabwriter.NewWriter(a.stdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "\tNAME\tBACKEND\tTARGET\tOUTPUT")
+	for _, name := range f.Names() {
+		p := f.Profiles[name]
+
+		current := ""
+		if name == inUse {
+			current = "*"
+		}
+		output := p.Output
+		if output == "" {
+			output = profile.OutputText
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", current, name, p.Backend, profileTarget(p), output)
+	}
+
+	return w.Flush()
+}
+
+func (a *Application) useProfile(c *cli.Context) error {
+	name := c.Args().First()
+	if name == "" {
+		return usageError("profile name must not be blank")
+	}
+
+	f, err := a.profiles()
+	if err != nil {
+		return err
+	}
+	if err := f.Use(name); err != nil {
+		return usageError("%v", err)
+	}
+	if err := f.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout(), "Using profile %q.\n", name)
+
+	return nil
+}
+
+func (a *Application) addProfile(c *cli.Context) error {
+	name := c.Args().First()
+	if name == "" {
+		return usageError("profile name must not be blank")
+	}
+
+	f, err := a.profiles()
+	if err != nil {
+		return err
+	}
+
+	p := &profile.Profile{
+		Name:    name,
+		Backend: c.String("backend"),
+		DB: profile.DB{
+			Host: c.String("db-host"),
+			Port: c.Int("db-port"),
+			Name: c.String("db-name"),
+			User: c.String("db-user"),
+			Pass: c.String("db-pass"),
+		},
+		URL:    c.String("url"),
+		Token:  c.String("token"),
+		Output: c.String("output"),
+	}
+	if err := f.Add(p); err != nil {
+		return usageError("%v", err)
+	}
+	if c.Bool("use") || len(f.Profiles) == 1 {
+		f.Current = name
+	}
+	if err := f.Save(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout(), "Added profile %q to %s.\n", name, f.Path)
+	if f.Current == name {
+		fmt.Fprintf(a.stdout(), "Using profile %q.\n", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/profile"
+)
+
+// profileApp returns an Application using the profiles file in dir, which
+// connects to svc and records the profile it connects with.
+func profileApp(dir string, svc gotodo.Service, connected **profile.Profile) (*Application, *bytes.Buffer, *bytes.Buffer) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	a := &Application{
+		Out:          out,
+		Err:          errOut,
+		ProfilesFile: filepath.Join(dir, "profiles.toml"),
+		Connect: func(p *profile.Profile) (gotodo.Service, error) {
+			*connected = p
+			return svc, nil
+		},
+	}
+
+	return a, out, errOut
+}
+
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	var connected *profile.Profile
+	runProfile := func(args ...string) (int, string, string) {
+		a, out, errOut := profileApp(dir, newMemService("Buy milk"), &connected)
+		err := a.Run(append([]string{"gotodocli"}, args...))
+
+		return ExitCode(err), out.String(), errOut.String()
+	}
+
+	code, out, _ := runProfile("profile", "list")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "No profile")
+
+	code, out, _ = runProfile("profile", "add", "personal", "--db-host", "127.0.0.1", "--db-port", "3307")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, `Using profile "personal".`)
+
+	code, _, _ = runProfile("profile", "add", "staging", "-b", "http", "--url", "https://todo.staging.example.com", "--output", "json")
+	assert.Equal(t, 0, code)
+
+	code, _, errOut := runProfile("profile", "add", "prod", "--backend", "http")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, errOut, `profile "prod": url must be set`)
+
+	code, out, _ = runProfile("profile", "list")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "   NAME      BACKEND   TARGET                            OUTPUT\n"+
+		"*  personal  database  127.0.0.1:3307                    text\n"+
+		"   staging   http      https://todo.staging.example.com  json\n", out)
+
+	code, out, _ = runProfile("getall")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Title: Buy milk")
+	if assert.NotNil(t, connected) {
+		assert.Equal(t, "personal", connected.Name)
+	}
+
+	code, out, _ = runProfile("--profile", "staging", "getall")
+	assert.Equal(t, 0, code)
+	assert.JSONEq(t, `[{"id":1,"title":"Buy milk","description":"","done":false}]`, out)
+	assert.Equal(t, "staging", connected.Name)
+
+	code, _, _ = runProfile("profile", "use", "staging")
+	assert.Equal(t, 0, code)
+	code, out, _ = runProfile("-o", "text", "get", "1")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Title: Buy milk")
+	assert.Equal(t, "staging", connected.Name)
+
+	code, _, errOut = runProfile("--profile", "prod", "getall")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, errOut, `profile "prod" does not exist`)
+
+	// Completions list the Todos of the profile given on the command line.
+	code, out, _ = runProfile("__complete", "-p", "personal", "done", "")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "1\tBuy milk\n", out)
+	assert.Equal(t, "personal", connected.Name)
+
+	code, out, _ = runProfile("__complete", "--profile", "")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "personal\t127.0.0.1:3307\nstaging\thttps://todo.staging.example.com\n", out)
+}
+
+func TestInvalidProfile(t *testing.T) {
+	dir := t.TempDir()
+	content := "current = \"prod\"\n\n[profiles.prod]\nbackend = \"grpc\"\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "profiles.toml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var connected *profile.Profile
+	runProfile := func(args ...string) (int, string, string) {
+		a, out, errOut := profileApp(dir, newMemService("Buy milk"), &connected)
+		err := a.Run(append([]string{"gotodocli"}, args...))
+
+		return ExitCode(err), out.String(), errOut.String()
+	}
+
+	code, _, errOut := runProfile("getall")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, errOut, `profile "prod": backend "grpc" must be either database or http`)
+
+	// The profile commands still work, to fix the file.
+	code, out, _ := runProfile("profile", "list")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "*  prod")
+
+	code, _, _ = runProfile("profile", "add", "personal", "--use")
+	assert.Equal(t, 0, code)
+	code, out, _ = runProfile("getall")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Title: Buy milk")
+	assert.Equal(t, "personal", connected.Name)
+}
+
+func TestConnect(t *testing.T) {
+	var connected *profile.Profile
+	a, _, _ := profileApp(t.TempDir(), nil, &connected)
+	a.Connect = func(p *profile.Profile) (gotodo.Service, error) {
+		return nil, errors.New("dial tcp: connection refused")
+	}
+
+	err := a.Run([]string{"gotodocli", "completion", "bash"})
+	assert.NoError(t, err)
+
+	err = a.Run([]string{"gotodocli", "getall"})
+	assert.Equal(t, ExitStorage, ExitCode(err))
+}
+
+func TestOutput(t *testing.T) {
+	svc := newMemService("Buy milk", "Buy eggs")
+
+	code, out, _ := run(svc, "--output", "json", "create", "-t", "Walk the dog")
+	assert.Equal(t, 0, code)
+	var todo gotodo.Todo
+	if assert.NoError(t, json.Unmarshal([]byte(out), &todo)) {
+		assert.Equal(t, "Walk the dog", todo.Title)
+	}
+
+	code, out, _ = run(svc, "-o", "json", "get", "1-2")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, `{"id":1,"title":"Buy milk","description":"","done":false}`+"\n"+
+		`{"id":2,"title":"Buy eggs","description":"","done":false}`+"\n", out)
+
+	code, _, errOut := run(svc, "-o", "yaml", "getall")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, errOut, `output "yaml" must be either text or json`)
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/scan"
+)
+
+// scanTree creates a todo for each new TODO, FIXME and XXX comment of a
+// directory, and marks as done the todos of the comments which are gone. The
+// location of a comment is the description of its todo, and is kept up to
+// date as lines move.
+func (a *Application) scanTree(c *cli.Context) (err error) {
+	if c.NArg() > 1 {
+		return usageError("scan takes a single directory")
+	}
+	dir := c.Args().First()
+	if dir == "" {
+		dir = "."
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return usageError("%s is not a directory", dir)
+	}
+
+	statePath := c.String("state")
+	if statePath == "" {
+		statePath = filepath.Join(dir, scan.StateFile)
+	}
+	state, err := scan.LoadState(statePath)
+	if err != nil {
+		return err
+	}
+
+	comments, err := scan.Dir(dir)
+	if err != nil {
+		return err
+	}
+
+	changes := state.Diff(comments)
+	if changes.Empty() {
+		a.printMessage(fmt.Sprintf("no change in %d comments", len(comments)))
+		return nil
+	}
+
+	dryRun := c.Bool("dry-run")
+	if !dryRun {
+		// Record what was done, even if a change fails.
+		defer func() {
+			if saveErr := state.Save(); err == nil {
+				err = saveErr
+			}
+		}()
+	}
+
+	for _, m := range changes.Moved {
+		todo, err := a.Service.Get(m.Tracked.ID)
+		if err != nil {
+			if err := getError(m.Tracked.ID, err); ExitCode(err) != ExitNotFound {
+				return err
+			}
+
+			// The todo was deleted; a new one is created below.
+			state.Untrack(m.Tracked)
+			changes.New = append(changes.New, m.To)
+			continue
+		}
+
+		if todo.Description == m.Tracked.Comment().Location() {
+			todo.Description = m.To.Location()
+			if dryRun {
+				a.printTodo("Would move todo:", todo)
+				continue
+			}
+			if err := a.Service.Edit(todo); err != nil {
+				return storageError(fmt.Errorf("todo %d: %w", todo.ID, err))
+			}
+			a.printTodo("Todo moved:", todo)
+		}
+		if !dryRun {
+			m.Tracked.Line = m.To.Line
+		}
+	}
+
+	for _, comment := range changes.New {
+		if dryRun {
+			a.printTodo("Would create todo:", &gotodo.Todo{Title: comment.Title(), Description: comment.Location()})
+			continue
+		}
+
+		todo, err := a.Service.Add(comment.Title(), comment.Location())
+		if err != nil {
+			return storageError(fmt.Errorf("%s: %w", comment.Location(), err))
+		}
+		state.Track(todo.ID, comment)
+		a.printTodo("Created todo:", todo)
+	}
+
+	for _, tracked := range changes.Gone {
+		todo, err := a.Service.Get(tracked.ID)
+		if err != nil {
+			if err := getError(tracked.ID, err); ExitCode(err) != ExitNotFound {
+				return err
+			}
+		} else if !todo.Done {
+			if dryRun {
+				a.printTodo("Would mark todo as done:", todo)
+				continue
+			}
+			if err := a.Service.MarkAsDone(todo); err != nil {
+				return storageError(fmt.Errorf("todo %d: %w", todo.ID, err))
+			}
+			a.printTodo("Todo marked as done:", todo)
+		}
+
+		if !dryRun {
+			state.Untrack(tracked)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/scan"
+)
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "main.go")
+	write := func(content string) {
+		assert.NoError(t, ioutil.WriteFile(source, []byte(content), 0644))
+	}
+	svc := newMemService("Buy milk")
+
+	write("package main\n\n// TODO: close the file\n// FIXME: handle errors\n")
+	code, out, _ := run(svc, "scan", "-n", dir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Would create todo:")
+	assert.Len(t, svc.GetAll(), 1)
+	_, err := os.Stat(filepath.Join(dir, scan.StateFile))
+	assert.True(t, os.IsNotExist(err))
+
+	code, out, _ = run(svc, "scan", dir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Title: TODO: close the file\nDescription: main.go:3\n")
+	assert.Contains(t, out, "Title: FIXME: handle errors\nDescription: main.go:4\n")
+	_, err = os.Stat(filepath.Join(dir, scan.StateFile))
+	assert.NoError(t, err)
+
+	code, out, _ = run(svc, "scan", dir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "no change in 2 comments")
+
+	write("package main\n\nimport \"os\"\n\n// TODO: close the file\n")
+	code, out, _ = run(svc, "scan", dir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Todo moved:")
+	assert.Contains(t, out, "Todo marked as done:")
+
+	todos := svc.GetAll()
+	if assert.Len(t, todos, 3) {
+		assert.Equal(t, "main.go:5", todos[1].Description)
+		assert.False(t, todos[1].Done)
+		assert.Equal(t, "FIXME: handle errors", todos[2].Title)
+		assert.True(t, todos[2].Done)
+	}
+
+	// A deleted todo is created again while its comment is there.
+	svc.Delete(todos[1])
+	write("package main\n\n// TODO: close the file\n")
+	code, out, _ = run(svc, "scan", dir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Created todo:\nID: 4\nTitle: TODO: close the file\nDescription: main.go:3\n")
+
+	code, _, errOut := run(svc, "scan", source)
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, errOut, "is not a directory")
+}
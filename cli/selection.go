@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+// maxRangeLength bounds the ranges of IDs, so that a typo such as "1-100000"
+// does not turn into as many lookups.
+const maxRangeLength = 1000
+
+// selected is a Todo selected by a command, or the error getting it.
+type selected struct {
+	todo *gotodo.Todo
+	err  error
+}
+
+// parseIDs parses IDs written as "3", ranges written as "3-7", and
+// comma-separated lists of those. IDs are returned once, in the order they
+// are first given.
+func parseIDs(args []string) ([]int, error) {
+	var ids []int
+	seen := map[int]bool{}
+
+	for _, arg := range args {
+		for _, part := range strings.Split(arg, ",") {
+			if part == "" {
+				continue
+			}
+
+			first, last, err := parseRange(part)
+			if err != nil {
+				return nil, err
+			}
+			for id := first; id <= last; id++ {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// parseRange parses a single ID, or a range of IDs, into its bounds.
+func parseRange(s string) (int, int, error) {
+	from, to := s, s
+	if i := strings.Index(s, "-"); i > 0 {
+		from, to = s[:i], s[i+1:]
+	}
+
+	first, err := strconv.Atoi(from)
+	if err != nil {
+		return 0, 0, usageError("invalid ID %q", s)
+	}
+	last, err := strconv.Atoi(to)
+	if err != nil {
+		return 0, 0, usageError("invalid ID %q", s)
+	}
+
+	if last < first {
+		return 0, 0, usageError("invalid range %q: %d is less than %d", s, last, first)
+	}
+	if last-first >= maxRangeLength {
+		return 0, 0, usageError("range %q is longer than %d IDs", s, maxRangeLength)
+	}
+
+	return first, last, nil
+}
+
+// matches tells whether the title or the description of todo contains match,
+// ignoring case.
+func matches(todo *gotodo.Todo, match string) bool {
+	match = strings.ToLower(match)
+
+	return strings.Contains(strings.ToLower(todo.Title), match) ||
+		strings.Contains(strings.ToLower(todo.Description), match)
+}
+
+// selectTodos returns the Todos whose IDs are the arguments of c, or those
+// returned by candidates if there is none, keeping only the ones matching the
+// --match flag if it is set. A Todo which can not be got is returned with its
+// error.
+func (a *Application) selectTodos(c *cli.Context, candidates func() []*gotodo.Todo) ([]selected, error) {
+	ids, err := parseIDs(c.Args())
+	if err != nil {
+		return nil, err
+	}
+
+	match := c.String("match")
+	if len(ids) == 0 && match == "" {
+		return nil, usageError("ID argument must not be blank")
+	}
+
+	var todos []selected
+	if len(ids) == 0 {
+		for _, todo := range candidates() {
+			if matches(todo, match) {
+				todos = append(todos, selected{todo: todo})
+			}
+		}
+	}
+	for _, id := range ids {
+		todo, err := a.Service.Get(id)
+		if err != nil {
+			todos = append(todos, selected{err: getError(id, err)})
+		} else if match == "" || matches(todo, match) {
+			todos = append(todos, selected{todo: todo})
+		}
+	}
+
+	if len(todos) == 0 {
+		return nil, notFoundError("no todo matches %q", match)
+	}
+
+	return todos, nil
+}
+
+// forEach calls action with every selected Todo, even after failures, which
+// are reported to stderr. A single Todo fails as the whole command does.
+func (a *Application) forEach(todos []selected, action func(todo *gotodo.Todo) error) error {
+	if len(todos) == 1 {
+		if todos[0].err != nil {
+			return todos[0].err
+		}
+
+		return action(todos[0].todo)
+	}
+
+	failed, code := 0, 0
+	for _, s := range todos {
+		err := s.err
+		if err == nil {
+			err = action(s.todo)
+		}
+		if err == nil {
+			continue
+		}
+
+		writeError(a.stderr(), err, a.jsonErrors)
+		failed++
+		if code == 0 {
+			code = ExitCode(err)
+		} else if code != ExitCode(err) {
+			code = ExitFailure
+		}
+	}
+
+	if failed > 0 {
+		return &Error{Code: code, Err: fmt.Errorf("%d of %d todos failed", failed, len(todos))}
+	}
+
+	return nil
+}
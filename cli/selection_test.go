@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIDs(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		ids  []int
+		err  string
+	}{
+		{[]string{"3"}, []int{3}, ""},
+		{[]string{"3", "1"}, []int{3, 1}, ""},
+		{[]string{"3-6"}, []int{3, 4, 5, 6}, ""},
+		{[]string{"1,3-4", "4", "2"}, []int{1, 3, 4, 2}, ""},
+		{[]string{"5-5"}, []int{5}, ""},
+		{nil, nil, ""},
+		{[]string{"one"}, nil, `invalid ID "one"`},
+		{[]string{"3-"}, nil, `invalid ID "3-"`},
+		{[]string{"7-3"}, nil, `invalid range "7-3"`},
+		{[]string{"1-100000"}, nil, `range "1-100000" is longer than 1000 IDs`},
+	} {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			ids, err := parseIDs(tc.args)
+
+			if tc.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.err)
+					assert.Equal(t, ExitUsage, ExitCode(err))
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.ids, ids)
+		})
+	}
+}
+
+// pendingIDs returns the IDs of the pending Todos of svc.
+func pendingIDs(svc *memService) []int {
+	ids := []int{}
+	for _, todo := range svc.GetPending() {
+		ids = append(ids, todo.ID)
+	}
+
+	return ids
+}
+
+func TestSelection(t *testing.T) {
+	svc := newMemService("Deploy the API", "Buy milk", "Deploy the CLI", "Buy eggs")
+
+	code, out, errOut := run(svc, "get", "1-2", "4")
+	assert.Equal(t, 0, code)
+	assert.Empty(t, errOut)
+	assert.Equal(t, 3, strings.Count(out, "ID: "))
+
+	code, out, _ = run(svc, "done", "--match", "deploy")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, 2, strings.Count(out, "Todo marked as done:"))
+	assert.Equal(t, []int{2, 4}, pendingIDs(svc))
+
+	code, _, errOut = run(svc, "get", "--match", "walk")
+	assert.Equal(t, ExitNotFound, code)
+	assert.Contains(t, errOut, `no todo matches "walk"`)
+
+	code, _, errOut = run(svc, "get", "--match", "milk", "1-3")
+	assert.Equal(t, 0, code)
+	assert.Empty(t, errOut)
+}
+
+func TestSelectionContinuesOnFailure(t *testing.T) {
+	svc := newMemService("Buy milk", "Buy eggs")
+
+	code, out, errOut := run(svc, "delete", "--yes", "1,42", "2")
+
+	assert.Equal(t, ExitNotFound, code)
+	assert.Equal(t, 2, strings.Count(out, "Todo deleted:"))
+	assert.Contains(t, errOut, "todo 42 not found")
+	assert.Contains(t, errOut, "1 of 3 todos failed")
+	assert.Empty(t, svc.GetAll())
+}
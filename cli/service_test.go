@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+// memService is an in-memory gotodo.Service.
+type memService struct {
+	mu     sync.Mutex
+	todos  []*gotodo.Todo
+	nextID int
+
+	// failure is returned by the calls which can fail, once set, as if the
+	// storage were down.
+	failure error
+}
+
+func newMemService(titles ...string) *memService {
+	s := &memService{}
+	for _, title := range titles {
+		s.Add(title, "")
+	}
+
+	return s
+}
+
+func (s *memService) Get(id int) (*gotodo.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failure != nil {
+		return nil, s.failure
+	}
+
+	for _, todo := range s.todos {
+		if todo.ID == id {
+			copied := *todo
+			return &copied, nil
+		}
+	}
+
+	return nil, errors.New("record not found")
+}
+
+func (s *memService) filter(keep func(todo *gotodo.Todo) bool) []*gotodo.Todo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := []*gotodo.Todo{}
+	for _, todo := range s.todos {
+		if keep(todo) {
+			copied := *todo
+			todos = append(todos, &copied)
+		}
+	}
+
+	return todos
+}
+
+func (s *memService) GetAll() []*gotodo.Todo {
+	return s.filter(func(todo *gotodo.Todo) bool { return true })
+}
+
+func (s *memService) GetPending() []*gotodo.Todo {
+	return s.filter(func(todo *gotodo.Todo) bool { return !todo.Done })
+}
+
+func (s *memService) GetFinished() []*gotodo.Todo {
+	return s.filter(func(todo *gotodo.Todo) bool { return todo.Done })
+}
+
+func (s *memService) Add(title string, description string) (*gotodo.Todo, error) {
+	if title == "" {
+		return nil, errors.New("title must not be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failure != nil {
+		return nil, s.failure
+	}
+
+	s.nextID++
+	todo := &gotodo.Todo{ID: s.nextID, Title: title, Description: description}
+	s.todos = append(s.todos, todo)
+
+	copied := *todo
+	return &copied, nil
+}
+
+func (s *memService) update(id int, apply func(todo *gotodo.Todo)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failure != nil {
+		return s.failure
+	}
+
+	for _, todo := range s.todos {
+		if todo.ID == id {
+			apply(todo)
+			return nil
+		}
+	}
+
+	return errors.New("record not found")
+}
+
+func (s *memService) Edit(todo *gotodo.Todo) error {
+	if todo.Title == "" {
+		return errors.New("title must not be empty")
+	}
+
+	return s.update(todo.ID, func(stored *gotodo.Todo) {
+		stored.Title = todo.Title
+		stored.Description = todo.Description
+	})
+}
+
+func (s *memService) MarkAsDone(todo *gotodo.Todo) error {
+	todo.Done = true
+	return s.update(todo.ID, func(stored *gotodo.Todo) {
+		stored.Done = true
+	})
+}
+
+func (s *memService) Delete(todo *gotodo.Todo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failure != nil {
+		return s.failure
+	}
+
+	for i, stored := range s.todos {
+		if stored.ID == todo.ID {
+			s.todos = append(s.todos[:i], s.todos[i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.New("record not found")
+}
+
+func (s *memService) DeleteFinished() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := []*gotodo.Todo{}
+	for _, todo := range s.todos {
+		if !todo.Done {
+			pending = append(pending, todo)
+		}
+	}
+	s.todos = pending
+}
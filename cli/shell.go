@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/peterh/liner"
+	"gopkg.in/urfave/cli.v1"
+)
+
+const shellPrompt = "gotodo> "
+
+// errExit is returned by runLine when the user asks to leave the shell.
+var errExit = errors.New("exit")
+
+// runShell reads commands from stdin and runs them with the same Service until
+// the input ends. Commands are read with line editing and history if stdin is
+// a terminal; otherwise, stdin is run as a script.
+func (a *Application) runShell(c *cli.Context) error {
+	if a.inShell {
+		return errors.New("already in a shell")
+	}
+	a.inShell = true
+	defer func() { a.inShell = false }()
+
+	if !stdinIsTerminal() {
+		return a.runScript(os.Stdin, a.stderr())
+	}
+
+	history := c.String("history")
+	if history == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		history = filepath.Join(home, ".gotodocli_history")
+	}
+
+	return a.runInteractive(history)
+}
+
+func (a *Application) runInteractive(history string) error {
+	line := liner.NewLiner()
+	defer line.Close()
+
+	line.SetCtrlCAborts(true)
+	line.SetTabCompletionStyle(liner.TabPrints)
+	line.SetCompleter(a.complete)
+
+	if f, err := os.Open(history); err == nil {
+		line.ReadHistory(f)
+		f.Close()
+	}
+	defer func() {
+		if f, err := os.Create(history); err == nil {
+			line.WriteHistory(f)
+			f.Close()
+		}
+	}()
+
+	fmt.Fprintln(a.stdout(), `Type a command, "help" to list them, or "exit" to leave.`)
+	for {
+		input, err := line.Prompt(shellPrompt)
+		if err == liner.ErrPromptAborted {
+			continue
+		}
+		if err == io.EOF {
+			fmt.Fprintln(a.stdout())
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(input) != "" {
+			line.AppendHistory(input)
+		}
+
+		err = a.runLine(input)
+		if err == errExit {
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintln(a.stderr(), "Error:", err)
+		}
+	}
+}
+
+// runScript runs the commands read from r, one per line, reporting the
+// failing ones to errw. Blank lines and lines starting with "#" are skipped.
+func (a *Application) runScript(r io.Reader, errw io.Writer) error {
+	failed := 0
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		err := a.runLine(scanner.Text())
+		if err == errExit {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(errw, "line %d: %v\n", n, err)
+			failed++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d commands failed", failed)
+	}
+
+	return nil
+}
+
+// runLine runs the command written on line.
+func (a *Application) runLine(line string) error {
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return nil
+	}
+
+	args, err := splitArgs(line)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	if args[0] == "exit" || args[0] == "quit" {
+		return errExit
+	}
+
+	// The shell reports errors itself, and must keep running after them.
+	exiter, errWriter := cli.OsExiter, cli.ErrWriter
+	cli.OsExiter, cli.ErrWriter = func(int) {}, ioutil.Discard
+	defer func() { cli.OsExiter, cli.ErrWriter = exiter, errWriter }()
+
+	app := a.newApp()
+	return app.Run(append([]string{app.Name}, args...))
+}
+
+// splitArgs splits line into arguments as a POSIX shell would, honoring
+// single quotes, double quotes and backslash escapes.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+
+	return args, nil
+}
+
+// complete returns the completions of line: command names for the first word,
+// then flags, or the IDs of the Todos for the commands taking some.
+func (a *Application) complete(line string) []string {
+	words := strings.Fields(line)
+	word := lastWord(line)
+	if word != "" {
+		// The last word is the one being completed.
+		words = words[:len(words)-1]
+	}
+	head := line[:len(line)-len(word)]
+
+	var commands []cli.Command
+	for _, command := range a.newApp().Commands {
+		if command.Name != "shell" && command.Name != "completion" {
+			commands = append(commands, command)
+		}
+	}
+
+	var completions []string
+	for _, candidate := range a.completions(commands, words, word) {
+		completions = append(completions, head+candidate.word+" ")
+	}
+	if len(words) == 0 {
+		for _, name := range []string{"exit", "help", "quit"} {
+			if strings.HasPrefix(name, word) {
+				completions = append(completions, head+name+" ")
+			}
+		}
+	}
+	sort.Strings(completions)
+
+	return completions
+}
+
+// lastWord returns the word being typed at the end of line, if any.
+func lastWord(line string) string {
+	i := strings.LastIndexAny(line, " \t")
+	return line[i+1:]
+}
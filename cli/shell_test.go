@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitArgs(t *testing.T) {
+	for line, expected := range map[string][]string{
+		``:                                    nil,
+		`  getall  --done `:                   {"getall", "--done"},
+		`create -t "Buy milk" -d 'Two "big"'`: {"create", "-t", "Buy milk", "-d", `Two "big"`},
+		`create -t Buy\ eggs -d ""`:           {"create", "-t", "Buy eggs", "-d", ""},
+		`edit 1 -t "say \"hi\""`:              {"edit", "1", "-t", `say "hi"`},
+	} {
+		args, err := splitArgs(line)
+
+		assert.NoError(t, err, line)
+		assert.Equal(t, expected, args, line)
+	}
+
+	_, err := splitArgs(`create -t "Buy milk`)
+	assert.Error(t, err)
+}
+
+func TestRunScript(t *testing.T) {
+	svc := newMemService()
+	a := &Application{Service: svc, Out: &bytes.Buffer{}}
+
+	script := `# Shopping
+create --title "Buy milk" --description "Two bottles"
+create --title "Buy eggs"
+
+done 1
+get 42
+nonsense
+delete --yes 2
+exit
+create --title "Never created"
+`
+	errw := &bytes.Buffer{}
+	err := a.runScript(strings.NewReader(script), errw)
+
+	assert.EqualError(t, err, "2 commands failed")
+	assert.Contains(t, errw.String(), "line 6: todo 42 not found")
+	assert.Contains(t, errw.String(), `line 7: unknown command "nonsense"`)
+
+	todos := svc.GetAll()
+	if assert.Len(t, todos, 1) {
+		assert.Equal(t, "Buy milk", todos[0].Title)
+		assert.True(t, todos[0].Done)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	svc := newMemService("Buy milk", "Buy eggs", "Walk the dog")
+	svc.MarkAsDone(svc.GetAll()[0])
+	a := &Application{Service: svc}
+
+	assert.Equal(t, []string{"delete ", "delete-finished ", "done "}, a.complete("d"))
+	assert.Equal(t, []string{"get 1 ", "get 2 ", "get 3 "}, a.complete("get "))
+	assert.Equal(t, []string{"done 2 ", "done 3 "}, a.complete("done "))
+	assert.Equal(t, []string{"getall --done "}, a.complete("getall --d"))
+	assert.Equal(t, []string{"get 1 2 ", "get 1 3 "}, a.complete("get 1 "))
+	assert.Empty(t, a.complete("edit 1 "))
+}
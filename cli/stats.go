@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/saifulwebid/gotodoapp/profile"
+	"github.com/saifulwebid/gotodoapp/stats"
+	"github.com/saifulwebid/gotodoapp/timestamps"
+)
+
+// barWidth is the length of the longest bar of the stats chart.
+const barWidth = 20
+
+// report returns the Report of the Todos, made by Service if it is a
+// stats.Reporter.
+func (a *Application) report(opts stats.Options) (*stats.Report, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, usageError("%v", err)
+	}
+
+	if reporter, ok := a.Service.(stats.Reporter); ok {
+		report, err := reporter.Stats(opts)
+		if err != nil {
+			return nil, storageError(err)
+		}
+		return report, nil
+	}
+
+	var times map[int]timestamps.Times
+	if timed, ok := a.Service.(timestamps.Timed); ok {
+		var err error
+		if times, err = timed.Times(); err != nil {
+			return nil, storageError(err)
+		}
+	}
+
+	return stats.Compute(a.Service, times, opts)
+}
+
+func (a *Application) stats(c *cli.Context) error {
+	report, err := a.report(stats.Options{
+		By:     c.String("by"),
+		Last:   c.Int("last"),
+		Oldest: c.Int("oldest"),
+	})
+	if err != nil {
+		return err
+	}
+
+	if a.output == profile.OutputJSON {
+		return json.NewEncoder(a.stdout()).Encode(report)
+	}
+
+	w := tabwriter.NewWriter(a.stdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Pending:\t%d\n", report.Pending)
+	fmt.Fprintf(w, "Finished:\t%d\n", report.Finished)
+	fmt.Fprintf(w, "Completion rate:\t%.0f%%\n", report.CompletionRate*100)
+
+	if len(report.History) > 0 {
+		created, completed := make([]int, len(report.History)), make([]int, len(report.History))
+		totalCreated, totalCompleted, highest := 0, 0, 0
+		for i, interval := range report.History {
+			created[i], completed[i] = interval.Created, interval.Completed
+			totalCreated += interval.Created
+			totalCompleted += interval.Completed
+			if interval.Created > highest {
+				highest = interval.Created
+			}
+			if interval.Completed > highest {
+				highest = interval.Completed
+			}
+		}
+
+		fmt.Fprintf(w, "\nLast %d %ss:\n", len(report.History), report.By)
+		fmt.Fprintf(w, "Created:\t%s  %d\n", stats.Sparkline(created), totalCreated)
+		fmt.Fprintf(w, "Completed:\t%s  %d\n", stats.Sparkline(completed), totalCompleted)
+
+		fmt.Fprintf(w, "\n%s\tCreated\t\tCompleted\n", map[string]string{stats.Day: "Day", stats.Week: "Week of"}[report.By])
+		for _, interval := range report.History {
+			row := fmt.Sprintf("%s\t%d\t%s\t%d", interval.Start.Format("2006-01-02"),
+				interval.Created, stats.Bar(interval.Created, highest, barWidth), interval.Completed)
+			if bar := stats.Bar(interval.Completed, highest, barWidth); bar != "" {
+				row += "\t" + bar
+			}
+			fmt.Fprintln(w, row)
+		}
+	}
+
+	if len(report.Oldest) > 0 {
+		fmt.Fprintf(w, "\nOldest pending todos:\n")
+		for _, open := range report.Oldest {
+			age := "created at an unknown time"
+			if open.CreatedAt != nil {
+				age = fmt.Sprintf("created %s, %s", open.CreatedAt.Local().Format("2006-01-02"), daysAgo(*open.CreatedAt))
+			}
+			fmt.Fprintf(w, "%6d  %s\t%s\n", open.ID, open.Title, age)
+		}
+	}
+
+	return w.Flush()
+}
+
+// daysAgo tells how many days ago t is, e.g. "today" or "3 days ago".
+func daysAgo(t time.Time) string {
+	switch days := int(time.Since(t).Hours() / 24); days {
+	case 0:
+		return "today"
+	case 1:
+		return "1 day ago"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
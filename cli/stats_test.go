@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/stats"
+	"github.com/saifulwebid/gotodoapp/timestamps"
+)
+
+func TestStats(t *testing.T) {
+	svc := timestamps.NewService(newMemService(), timestamps.NewMemoryStore())
+	milk, _ := svc.Add("Buy milk", "")
+	svc.Add("Buy eggs", "")
+	svc.MarkAsDone(milk)
+
+	out := &bytes.Buffer{}
+	a := &Application{Service: svc, Out: out}
+	err := a.Run([]string{"gotodocli", "stats", "--last", "3"})
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "Pending:          1\n")
+	assert.Contains(t, out.String(), "Completion rate:  50%\n")
+	assert.Contains(t, out.String(), "Created:    __@  2\n")
+	assert.Contains(t, out.String(), "Completed:  __@  1\n")
+	assert.Regexp(t, `\d{4}-\d\d-\d\d\s+2\s+#{20}\s+1\s+#{10}\n`, out.String())
+	assert.Contains(t, out.String(), "Oldest pending todos:")
+	assert.Regexp(t, `2  Buy eggs\s+created \d{4}-\d\d-\d\d, today\n`, out.String())
+
+	out.Reset()
+	err = a.Run([]string{"gotodocli", "-o", "json", "stats", "--by", "week"})
+	assert.NoError(t, err)
+	var report stats.Report
+	if assert.NoError(t, json.Unmarshal(out.Bytes(), &report)) {
+		assert.Len(t, report.History, stats.DefaultWeeks)
+	}
+
+	code, _, errOut := run(newMemService(), "stats", "--by", "month")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, errOut, `interval "month" must be either day or week`)
+}
+
+func TestStatsWithoutTimestamps(t *testing.T) {
+	code, out, _ := run(newMemService("Buy milk"), "stats")
+
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Completion rate:  0%\n")
+	assert.NotContains(t, out, "Created:")
+	assert.Contains(t, out, "created at an unknown time")
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/timestamps"
+)
+
+func TestTimes(t *testing.T) {
+	now := time.Date(2026, 10, 12, 9, 30, 0, 0, time.Local)
+	svc := timestamps.NewService(newMemService(), timestamps.NewMemoryStore())
+	svc.Now = func() time.Time { return now }
+	milk, _ := svc.Add("Buy milk", "")
+	now = now.AddDate(0, 0, 7)
+	svc.Add("Buy eggs", "")
+	svc.MarkAsDone(milk)
+
+	runTimed := func(args ...string) (int, string, string) {
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		a := &Application{Service: svc, Out: out, Err: errOut}
+		err := a.Run(append([]string{"gotodocli"}, args...))
+
+		return ExitCode(err), out.String(), errOut.String()
+	}
+
+	code, out, _ := runTimed("get", "1")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "ID: 1\nTitle: Buy milk\nDescription: \nDone: Finished\n"+
+		"Created: 2026-10-12 09:30\nUpdated: 2026-10-19 09:30\nCompleted: 2026-10-19 09:30\n\n", out)
+
+	code, out, _ = runTimed("-o", "json", "getall", "--since", "2026-10-15")
+	assert.Equal(t, 0, code)
+	var todos []*timestamps.Todo
+	if assert.NoError(t, json.Unmarshal([]byte(out), &todos)) && assert.Len(t, todos, 1) {
+		assert.Equal(t, "Buy eggs", todos[0].Title)
+		assert.True(t, todos[0].CreatedAt.Equal(now))
+		assert.Nil(t, todos[0].CompletedAt)
+	}
+
+	code, out, _ = runTimed("getall", "--timestamp", "completed", "--until", "2026-10-19")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Title: Buy milk")
+	assert.NotContains(t, out, "Title: Buy eggs")
+
+	code, _, errOut := runTimed("getall", "--since", "last week")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, errOut, `since: "last week" must be a time`)
+
+	code, _, errOut = run(newMemService("Buy milk"), "getall", "--since", "7d")
+	assert.Equal(t, ExitUsage, code)
+	assert.Contains(t, errOut, "not recorded")
+}
@@ -0,0 +1,482 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+// tuiFilter selects which Todos the terminal UI lists.
+type tuiFilter int
+
+const (
+	filterAll tuiFilter = iota
+	filterPending
+	filterFinished
+)
+
+var filterNames = []string{"All", "Pending", "Finished"}
+
+// tuiMode tells what the keyboard is currently used for.
+type tuiMode int
+
+const (
+	modeBrowse tuiMode = iota
+	modeSearch
+	modeInput
+	modeConfirm
+)
+
+const tuiHelp = "↑↓ move  Tab filter  / search  a add  e edit  Space done  x delete  r reload  q quit"
+
+// tui is the state of the terminal UI. It is driven by handleKey, and drawn on
+// screen by draw.
+type tui struct {
+	service gotodo.Service
+	screen  tcell.Screen
+
+	filter   tuiFilter
+	search   string
+	todos    []*gotodo.Todo
+	selected int
+
+	mode    tuiMode
+	prompt  string
+	input   []rune
+	submit  func(value string)
+	confirm func()
+
+	status string
+	quit   bool
+}
+
+func newTUI(svc gotodo.Service, screen tcell.Screen) *tui {
+	t := &tui{service: svc, screen: screen}
+	t.reload()
+
+	return t
+}
+
+// runTUI runs the terminal UI until the user quits.
+func (a *Application) runTUI(c *cli.Context) error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+	if err := screen.Init(); err != nil {
+		return err
+	}
+	defer screen.Fini()
+
+	t := newTUI(a.Service, screen)
+	for !t.quit {
+		t.draw()
+
+		switch ev := screen.PollEvent().(type) {
+		case *tcell.EventKey:
+			t.handleKey(ev)
+		case *tcell.EventResize:
+			screen.Sync()
+		}
+	}
+
+	return nil
+}
+
+// reload fetches the Todos matching the filter and the search again, keeping
+// the selected Todo selected if it is still listed.
+func (t *tui) reload() {
+	selectedID := 0
+	if todo := t.current(); todo != nil {
+		selectedID = todo.ID
+	}
+
+	var todos []*gotodo.Todo
+	switch t.filter {
+	case filterPending:
+		todos = t.service.GetPending()
+	case filterFinished:
+		todos = t.service.GetFinished()
+	default:
+		todos = t.service.GetAll()
+	}
+
+	t.todos = t.todos[:0]
+	search := strings.ToLower(t.search)
+	for _, todo := range todos {
+		if search == "" ||
+			strings.Contains(strings.ToLower(todo.Title), search) ||
+			strings.Contains(strings.ToLower(todo.Description), search) {
+			t.todos = append(t.todos, todo)
+		}
+	}
+
+	t.selected = 0
+	for i, todo := range t.todos {
+		if todo.ID == selectedID {
+			t.selected = i
+		}
+	}
+}
+
+// current returns the selected Todo, or nil if no Todo is listed.
+func (t *tui) current() *gotodo.Todo {
+	if t.selected < 0 || t.selected >= len(t.todos) {
+		return nil
+	}
+
+	return t.todos[t.selected]
+}
+
+// ask prompts for a value, initially set to value, which is passed to submit
+// once the user presses Enter.
+func (t *tui) ask(prompt string, value string, submit func(value string)) {
+	t.mode = modeInput
+	t.prompt = prompt
+	t.input = []rune(value)
+	t.submit = submit
+}
+
+func (t *tui) handleKey(ev *tcell.EventKey) {
+	if ev.Key() == tcell.KeyCtrlC {
+		t.quit = true
+		return
+	}
+
+	switch t.mode {
+	case modeSearch:
+		t.handleSearchKey(ev)
+	case modeInput:
+		t.handleInputKey(ev)
+	case modeConfirm:
+		t.mode = modeBrowse
+		if ev.Key() == tcell.KeyRune && (ev.Rune() == 'y' || ev.Rune() == 'Y') {
+			t.confirm()
+		} else {
+			t.status = "Cancelled"
+		}
+	default:
+		t.handleBrowseKey(ev)
+	}
+}
+
+func (t *tui) handleBrowseKey(ev *tcell.EventKey) {
+	t.status = ""
+
+	switch ev.Key() {
+	case tcell.KeyUp:
+		t.move(-1)
+	case tcell.KeyDown:
+		t.move(1)
+	case tcell.KeyHome:
+		t.selected = 0
+	case tcell.KeyEnd:
+		t.selected = len(t.todos) - 1
+	case tcell.KeyTab:
+		t.filter = (t.filter + 1) % 3
+		t.reload()
+	case tcell.KeyBacktab:
+		t.filter = (t.filter + 2) % 3
+		t.reload()
+	case tcell.KeyDelete:
+		t.delete()
+	case tcell.KeyEscape:
+		t.quit = true
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'k':
+			t.move(-1)
+		case 'j':
+			t.move(1)
+		case 'g':
+			t.selected = 0
+		case 'G':
+			t.selected = len(t.todos) - 1
+		case '/':
+			t.mode = modeSearch
+		case 'a':
+			t.add()
+		case 'e':
+			t.edit()
+		case ' ', 'd':
+			t.markAsDone()
+		case 'x':
+			t.delete()
+		case 'r':
+			t.reload()
+		case 'q':
+			t.quit = true
+		}
+	}
+}
+
+func (t *tui) move(delta int) {
+	t.selected += delta
+	if t.selected >= len(t.todos) {
+		t.selected = len(t.todos) - 1
+	}
+	if t.selected < 0 {
+		t.selected = 0
+	}
+}
+
+func (t *tui) handleSearchKey(ev *tcell.EventKey) {
+	search := []rune(t.search)
+
+	switch ev.Key() {
+	case tcell.KeyEnter:
+		t.mode = modeBrowse
+		return
+	case tcell.KeyEscape:
+		t.mode = modeBrowse
+		search = nil
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(search) > 0 {
+			search = search[:len(search)-1]
+		}
+	case tcell.KeyRune:
+		search = append(search, ev.Rune())
+	default:
+		return
+	}
+
+	t.search = string(search)
+	t.reload()
+}
+
+func (t *tui) handleInputKey(ev *tcell.EventKey) {
+	switch ev.Key() {
+	case tcell.KeyEnter:
+		t.mode = modeBrowse
+		t.submit(string(t.input))
+	case tcell.KeyEscape:
+		t.mode = modeBrowse
+		t.status = "Cancelled"
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(t.input) > 0 {
+			t.input = t.input[:len(t.input)-1]
+		}
+	case tcell.KeyCtrlU:
+		t.input = nil
+	case tcell.KeyRune:
+		t.input = append(t.input, ev.Rune())
+	}
+}
+
+func (t *tui) add() {
+	t.ask("Title", "", func(title string) {
+		t.ask("Description", "", func(description string) {
+			todo, err := t.service.Add(title, description)
+			if err != nil {
+				t.status = "Cannot create todo: " + err.Error()
+				return
+			}
+
+			t.reload()
+			for i := range t.todos {
+				if t.todos[i].ID == todo.ID {
+					t.selected = i
+				}
+			}
+			t.status = fmt.Sprintf("Created todo %d", todo.ID)
+		})
+	})
+}
+
+func (t *tui) edit() {
+	todo := t.current()
+	if todo == nil {
+		return
+	}
+
+	t.ask("Title", todo.Title, func(title string) {
+		t.ask("Description", todo.Description, func(description string) {
+			todo.Title = title
+			todo.Description = description
+			if err := t.service.Edit(todo); err != nil {
+				t.status = "Cannot edit todo: " + err.Error()
+			} else {
+				t.status = fmt.Sprintf("Edited todo %d", todo.ID)
+			}
+			t.reload()
+		})
+	})
+}
+
+func (t *tui) markAsDone() {
+	todo := t.current()
+	if todo == nil {
+		return
+	}
+	if todo.Done {
+		t.status = "Todo is already done"
+		return
+	}
+
+	if err := t.service.MarkAsDone(todo); err != nil {
+		t.status = "Cannot mark todo as done: " + err.Error()
+	} else {
+		t.status = fmt.Sprintf("Marked todo %d as done", todo.ID)
+	}
+	t.reload()
+}
+
+func (t *tui) delete() {
+	todo := t.current()
+	if todo == nil {
+		return
+	}
+
+	t.mode = modeConfirm
+	t.prompt = fmt.Sprintf("Delete todo %d? (y/n)", todo.ID)
+	t.confirm = func() {
+		if err := t.service.Delete(todo); err != nil {
+			t.status = "Cannot delete todo: " + err.Error()
+		} else {
+			t.status = fmt.Sprintf("Deleted todo %d", todo.ID)
+		}
+		t.reload()
+	}
+}
+
+// drawText draws s from (x, y), clipped at width cells. It returns the number
+// of cells drawn.
+func (t *tui) drawText(x, y, width int, s string, style tcell.Style) int {
+	drawn := 0
+	for _, r := range s {
+		w := runewidth.RuneWidth(r)
+		if drawn+w > width {
+			break
+		}
+		t.screen.SetContent(x+drawn, y, r, nil, style)
+		drawn += w
+	}
+
+	return drawn
+}
+
+// wrap splits s into lines of at most width cells, breaking between words.
+func wrap(s string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && runewidth.StringWidth(line+" "+word) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+func (t *tui) draw() {
+	t.screen.Clear()
+	width, height := t.screen.Size()
+	normal := tcell.StyleDefault
+	bold := normal.Bold(true)
+	reverse := normal.Reverse(true)
+
+	// Header: filters and search.
+	x := t.drawText(0, 0, width, "gotodo  ", bold)
+	for i, name := range filterNames {
+		style := normal
+		if tuiFilter(i) == t.filter {
+			style = reverse
+		}
+		x += t.drawText(x, 0, width-x, " "+name+" ", style)
+	}
+	if t.search != "" || t.mode == modeSearch {
+		t.drawText(x, 0, width-x, "  Search: "+t.search, normal)
+	}
+
+	// List, and the detail pane on its right.
+	listWidth := width * 3 / 5
+	rows := height - 4
+	offset := 0
+	if t.selected >= rows {
+		offset = t.selected - rows + 1
+	}
+	for row := 0; row < rows && offset+row < len(t.todos); row++ {
+		todo := t.todos[offset+row]
+		mark := "[ ]"
+		if todo.Done {
+			mark = "[x]"
+		}
+
+		style := normal
+		if offset+row == t.selected {
+			style = reverse
+		}
+		line := fmt.Sprintf("%s %4d  %s", mark, todo.ID, todo.Title)
+		line += strings.Repeat(" ", max(0, listWidth-1-runewidth.StringWidth(line)))
+		t.drawText(0, 2+row, listWidth-1, line, style)
+	}
+	if len(t.todos) == 0 {
+		t.drawText(0, 2, listWidth-1, "No todos", normal)
+	}
+
+	for y := 2; y < height-2; y++ {
+		t.screen.SetContent(listWidth, y, '│', nil, normal)
+	}
+
+	if todo := t.current(); todo != nil {
+		paneX := listWidth + 2
+		paneWidth := width - paneX
+		state := "Pending"
+		if todo.Done {
+			state = "Finished"
+		}
+
+		y := 2
+		for _, line := range []string{"ID: " + strconv.Itoa(todo.ID), "Status: " + state} {
+			t.drawText(paneX, y, paneWidth, line, normal)
+			y++
+		}
+		y++
+		for _, line := range wrap(todo.Title, paneWidth) {
+			t.drawText(paneX, y, paneWidth, line, bold)
+			y++
+		}
+		y++
+		for _, line := range wrap(todo.Description, paneWidth) {
+			if y >= height-2 {
+				break
+			}
+			t.drawText(paneX, y, paneWidth, line, normal)
+			y++
+		}
+	}
+
+	// Prompt or status, and help.
+	t.screen.HideCursor()
+	switch t.mode {
+	case modeInput:
+		x := t.drawText(0, height-2, width, t.prompt+": ", bold)
+		x += t.drawText(x, height-2, width-x, string(t.input), normal)
+		t.screen.ShowCursor(x, height-2)
+	case modeSearch:
+		x := t.drawText(0, height-2, width, "Search: ", bold)
+		x += t.drawText(x, height-2, width-x, t.search, normal)
+		t.screen.ShowCursor(x, height-2)
+	case modeConfirm:
+		t.drawText(0, height-2, width, t.prompt, bold)
+	default:
+		t.drawText(0, height-2, width, t.status, normal)
+	}
+	t.drawText(0, height-1, width, tuiHelp, normal.Dim(true))
+
+	t.screen.Show()
+}
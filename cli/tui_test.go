@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTUI(t *testing.T, svc *memService) *tui {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(100, 20)
+	t.Cleanup(screen.Fini)
+
+	return newTUI(svc, screen)
+}
+
+// press handles keys as typed by the user. Runes are typed as is, except for
+// the names of special keys in angle brackets, e.g. "<Enter>".
+func press(ui *tui, keys string) {
+	special := map[string]tcell.Key{
+		"<Enter>": tcell.KeyEnter,
+		"<Esc>":   tcell.KeyEscape,
+		"<Tab>":   tcell.KeyTab,
+		"<Up>":    tcell.KeyUp,
+		"<Down>":  tcell.KeyDown,
+		"<BS>":    tcell.KeyBackspace2,
+	}
+
+	for keys != "" {
+		handled := false
+		for name, key := range special {
+			if strings.HasPrefix(keys, name) {
+				ui.handleKey(tcell.NewEventKey(key, 0, tcell.ModNone))
+				keys = keys[len(name):]
+				handled = true
+				break
+			}
+		}
+		if !handled {
+			r := []rune(keys)[0]
+			ui.handleKey(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+			keys = keys[len(string(r)):]
+		}
+	}
+}
+
+// screenText returns the text drawn on the screen of ui, line by line.
+func screenText(ui *tui) string {
+	ui.draw()
+
+	cells, width, _ := ui.screen.(tcell.SimulationScreen).GetContents()
+	var b strings.Builder
+	for i, cell := range cells {
+		if len(cell.Runes) > 0 {
+			b.WriteRune(cell.Runes[0])
+		} else {
+			b.WriteRune(' ')
+		}
+		if (i+1)%width == 0 {
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+func titles(ui *tui) []string {
+	list := []string{}
+	for _, todo := range ui.todos {
+		list = append(list, todo.Title)
+	}
+
+	return list
+}
+
+func TestTUI(t *testing.T) {
+	t.Run("navigate and filter", func(t *testing.T) {
+		svc := newMemService("Buy milk", "Buy eggs", "Walk the dog")
+		ui := newTestTUI(t, svc)
+
+		assert.Equal(t, []string{"Buy milk", "Buy eggs", "Walk the dog"}, titles(ui))
+
+		press(ui, "jj<Down>")
+		assert.Equal(t, "Walk the dog", ui.current().Title)
+		press(ui, "<Up>")
+		assert.Equal(t, "Buy eggs", ui.current().Title)
+
+		press(ui, " ")
+		assert.True(t, svc.GetAll()[1].Done)
+
+		press(ui, "<Tab>")
+		assert.Equal(t, []string{"Buy milk", "Walk the dog"}, titles(ui))
+		press(ui, "<Tab>")
+		assert.Equal(t, []string{"Buy eggs"}, titles(ui))
+		press(ui, "<Tab>")
+		assert.Len(t, ui.todos, 3)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		ui := newTestTUI(t, newMemService("Buy milk", "Buy eggs", "Walk the dog"))
+
+		press(ui, "/buy<BS><BS><BS>walk<Enter>")
+		assert.Equal(t, []string{"Walk the dog"}, titles(ui))
+		assert.Contains(t, screenText(ui), "Search: walk")
+
+		press(ui, "/<Esc>")
+		assert.Len(t, ui.todos, 3)
+	})
+
+	t.Run("create, edit and delete", func(t *testing.T) {
+		svc := newMemService("Buy milk")
+		ui := newTestTUI(t, svc)
+
+		press(ui, "aBuy eggs<Enter>A dozen<Enter>")
+		assert.Equal(t, "Created todo 2", ui.status)
+		assert.Equal(t, "Buy eggs", ui.current().Title)
+
+		press(ui, "e<BS><BS><BS><BS>bread<Enter><Enter>")
+		todo, _ := svc.Get(2)
+		assert.Equal(t, "Buy bread", todo.Title)
+		assert.Equal(t, "A dozen", todo.Description)
+
+		press(ui, "xn")
+		assert.Len(t, svc.GetAll(), 2)
+		press(ui, "xy")
+		assert.Equal(t, []string{"Buy milk"}, titles(ui))
+	})
+
+	t.Run("errors are shown", func(t *testing.T) {
+		ui := newTestTUI(t, newMemService())
+
+		press(ui, "a<Enter><Enter>")
+		assert.Contains(t, screenText(ui), "Cannot create todo: title must not be empty")
+	})
+
+	t.Run("detail pane", func(t *testing.T) {
+		svc := newMemService()
+		svc.Add("Buy milk", "Two bottles of full cream milk")
+		ui := newTestTUI(t, svc)
+
+		text := screenText(ui)
+		assert.Contains(t, text, "[ ]    1  Buy milk")
+		assert.Contains(t, text, "Status: Pending")
+		assert.Contains(t, text, "Two bottles of full cream milk")
+	})
+
+	t.Run("quit", func(t *testing.T) {
+		ui := newTestTUI(t, newMemService())
+
+		press(ui, "q")
+		assert.True(t, ui.quit)
+	})
+}
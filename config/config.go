@@ -0,0 +1,489 @@
+// Package config loads the configuration shared by gotodoapp binaries.
+//
+// Every setting is looked up, from the lowest to the highest precedence, in:
+// its default value, a TOML or YAML configuration file, a .env file, the
+// environment, and command line flags.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+	"github.com/subosito/gotenv"
+	"gopkg.in/yaml.v2"
+)
+
+// Config holds every setting of gotodoapp binaries.
+//
+// The "key" tag names a setting in configuration files, where dots separate
+// tables, and in flags, where dots and underscores become dashes. The "env"
+// tag names its environment variable. Lists are written comma-separated,
+// except in configuration files where they may also be arrays.
+type Config struct {
+	DBHost string `key:"db.host" env:"GOTODO_DB_HOST" default:"127.0.0.1"`
+	DBPort int    `key:"db.port" env:"GOTODO_DB_PORT" default:"3306"`
+	DBName string `key:"db.name" env:"GOTODO_DB_NAME" default:"gotodo"`
+	DBUser string `key:"db.user" env:"GOTODO_DB_USER" default:"gotodo"`
+	DBPass string `key:"db.pass" env:"GOTODO_DB_PASS" secret:"true"`
+
+	APIPort              int           `key:"api.port" env:"GOTODO_API_PORT" default:"8080"`
+	APIValidateRequests  bool          `key:"api.validate_requests" env:"GOTODO_API_VALIDATE_REQUESTS" default:"false"`
+	APIReadTimeout       time.Duration `key:"api.read_timeout" env:"GOTODO_API_READ_TIMEOUT" default:"15s"`
+	APIReadHeaderTimeout time.Duration `key:"api.read_header_timeout" env:"GOTODO_API_READ_HEADER_TIMEOUT" default:"5s"`
+	APIWriteTimeout      time.Duration `key:"api.write_timeout" env:"GOTODO_API_WRITE_TIMEOUT" default:"30s"`
+	APIIdleTimeout       time.Duration `key:"api.idle_timeout" env:"GOTODO_API_IDLE_TIMEOUT" default:"2m"`
+	APIMaxHeaderBytes    int           `key:"api.max_header_bytes" env:"GOTODO_API_MAX_HEADER_BYTES" default:"1048576"`
+	APIShutdownTimeout   time.Duration `key:"api.shutdown_timeout" env:"GOTODO_API_SHUTDOWN_TIMEOUT" default:"20s"`
+	APICacheMaxAge       time.Duration `key:"api.cache_max_age" env:"GOTODO_API_CACHE_MAX_AGE" default:"0s"`
+	APIAuthToken         string        `key:"api.auth_token" env:"GOTODO_API_AUTH_TOKEN" secret:"true"`
+
+	CompressionEnabled bool `key:"api.compression.enabled" env:"GOTODO_API_COMPRESSION_ENABLED" default:"true"`
+	CompressionMinSize int  `key:"api.compression.min_size" env:"GOTODO_API_COMPRESSION_MIN_SIZE" default:"1024"`
+
+	GRPCPort int `key:"grpc.port" env:"GOTODO_GRPC_PORT"`
+
+	TLSCertFile             string        `key:"api.tls.cert_file" env:"GOTODO_API_TLS_CERT_FILE"`
+	TLSKeyFile              string        `key:"api.tls.key_file" env:"GOTODO_API_TLS_KEY_FILE"`
+	TLSReloadInterval       time.Duration `key:"api.tls.reload_interval" env:"GOTODO_API_TLS_RELOAD_INTERVAL" default:"1m"`
+	TLSClientCAFile         string        `key:"api.tls.client_ca_file" env:"GOTODO_API_TLS_CLIENT_CA_FILE"`
+	TLSClientIdentitiesFile string        `key:"api.tls.client_identities_file" env:"GOTODO_API_TLS_CLIENT_IDENTITIES_FILE"`
+	TLSRedirectPort         int           `key:"api.tls.redirect_port" env:"GOTODO_API_TLS_REDIRECT_PORT"`
+
+	CORSAllowedOrigins   []string      `key:"api.cors.allowed_origins" env:"GOTODO_API_CORS_ALLOWED_ORIGINS"`
+	CORSAllowedMethods   []string      `key:"api.cors.allowed_methods" env:"GOTODO_API_CORS_ALLOWED_METHODS"`
+	CORSAllowedHeaders   []string      `key:"api.cors.allowed_headers" env:"GOTODO_API_CORS_ALLOWED_HEADERS"`
+	CORSExposedHeaders   []string      `key:"api.cors.exposed_headers" env:"GOTODO_API_CORS_EXPOSED_HEADERS" default:"X-Request-ID"`
+	CORSAllowCredentials bool          `key:"api.cors.allow_credentials" env:"GOTODO_API_CORS_ALLOW_CREDENTIALS" default:"false"`
+	CORSMaxAge           time.Duration `key:"api.cors.max_age" env:"GOTODO_API_CORS_MAX_AGE" default:"10m"`
+
+	RateLimitEnabled        bool          `key:"api.rate_limit.enabled" env:"GOTODO_API_RATE_LIMIT_ENABLED" default:"false"`
+	RateLimitReadRate       float64       `key:"api.rate_limit.read_rate" env:"GOTODO_API_RATE_LIMIT_READ_RATE" default:"10"`
+	RateLimitReadBurst      int           `key:"api.rate_limit.read_burst" env:"GOTODO_API_RATE_LIMIT_READ_BURST" default:"20"`
+	RateLimitWriteRate      float64       `key:"api.rate_limit.write_rate" env:"GOTODO_API_RATE_LIMIT_WRITE_RATE" default:"2"`
+	RateLimitWriteBurst     int           `key:"api.rate_limit.write_burst" env:"GOTODO_API_RATE_LIMIT_WRITE_BURST" default:"5"`
+	RateLimitTrustedProxies []string      `key:"api.rate_limit.trusted_proxies" env:"GOTODO_API_RATE_LIMIT_TRUSTED_PROXIES"`
+	RateLimitEvictAfter     time.Duration `key:"api.rate_limit.evict_after" env:"GOTODO_API_RATE_LIMIT_EVICT_AFTER" default:"10m"`
+
+	CLIConfirmThreshold int `key:"cli.confirm_threshold" env:"GOTODO_CLI_CONFIRM_THRESHOLD" default:"5"`
+
+	LogLevel  string `key:"log.level" env:"GOTODO_LOG_LEVEL" default:"info"`
+	LogFormat string `key:"log.format" env:"GOTODO_LOG_FORMAT" default:"json"`
+
+	// sources records where each setting is taken from, by key.
+	sources map[string]string
+}
+
+// Options tells Load where to look for settings.
+type Options struct {
+	// File is the path of a TOML (.toml) or YAML (.yaml, .yml) configuration
+	// file. If empty, GOTODO_CONFIG environment variable is used; if that is
+	// empty too, no configuration file is read.
+	File string
+
+	// EnvFile is the path of a .env file. It defaults to ".env"; a missing
+	// file is ignored.
+	EnvFile string
+
+	// Flags are settings given on the command line, by key. See RegisterFlags.
+	Flags map[string]string
+}
+
+type field struct {
+	index   int
+	key     string
+	env     string
+	def     string
+	secret  bool
+	kind    reflect.Type
+	flagKey string
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func fields() []field {
+	t := reflect.TypeOf(Config{})
+
+	var fs []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := f.Tag.Get("key")
+		if key == "" {
+			continue
+		}
+
+		fs = append(fs, field{
+			index:   i,
+			key:     key,
+			env:     f.Tag.Get("env"),
+			def:     f.Tag.Get("default"),
+			secret:  f.Tag.Get("secret") == "true",
+			kind:    f.Type,
+			flagKey: strings.NewReplacer(".", "-", "_", "-").Replace(key),
+		})
+	}
+
+	return fs
+}
+
+// Load reads the configuration as described by opts, and validates it.
+func Load(opts Options) (*Config, error) {
+	cfg := &Config{sources: map[string]string{}}
+	fs := fields()
+
+	for _, f := range fs {
+		if err := cfg.set(f, f.def, "default"); err != nil {
+			return nil, err
+		}
+	}
+
+	file := opts.File
+	if file == "" {
+		file = os.Getenv("GOTODO_CONFIG")
+	}
+	if file != "" {
+		values, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range fs {
+			if value, ok := values[f.key]; ok {
+				if err := cfg.set(f, value, "file"); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	envFile := opts.EnvFile
+	if envFile == "" {
+		envFile = ".env"
+	}
+	dotenv, err := readEnvFile(envFile)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range fs {
+		if value, ok := dotenv[f.env]; ok {
+			if err := cfg.set(f, value, ".env"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, f := range fs {
+		if value, ok := os.LookupEnv(f.env); ok {
+			if err := cfg.set(f, value, "env"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, f := range fs {
+		if value, ok := opts.Flags[f.key]; ok {
+			if err := cfg.set(f, value, "flag"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) set(f field, value string, source string) error {
+	v := reflect.ValueOf(c).Elem().Field(f.index)
+
+	switch {
+	case f.kind == durationType:
+		d := time.Duration(0)
+		if value != "" {
+			var err error
+			if d, err = time.ParseDuration(value); err != nil {
+				return fmt.Errorf("%s (%s): invalid duration %q", f.key, source, value)
+			}
+		}
+		v.SetInt(int64(d))
+	case f.kind.Kind() == reflect.Int:
+		n := 0
+		if value != "" {
+			var err error
+			if n, err = strconv.Atoi(value); err != nil {
+				return fmt.Errorf("%s (%s): invalid number %q", f.key, source, value)
+			}
+		}
+		v.SetInt(int64(n))
+	case f.kind.Kind() == reflect.Float64:
+		x := 0.0
+		if value != "" {
+			var err error
+			if x, err = strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("%s (%s): invalid number %q", f.key, source, value)
+			}
+		}
+		v.SetFloat(x)
+	case f.kind.Kind() == reflect.Bool:
+		b := false
+		if value != "" {
+			var err error
+			if b, err = strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("%s (%s): invalid boolean %q", f.key, source, value)
+			}
+		}
+		v.SetBool(b)
+	case f.kind.Kind() == reflect.Slice:
+		list := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		v.Set(reflect.ValueOf(list))
+	default:
+		v.SetString(value)
+	}
+
+	c.sources[f.key] = source
+
+	return nil
+}
+
+// Validate checks that the settings are consistent.
+func (c *Config) Validate() error {
+	var errs []string
+
+	checkPort := func(name string, port int, optional bool) {
+		if optional && port == 0 {
+			return
+		}
+		if port < 1 || port > 65535 {
+			errs = append(errs, fmt.Sprintf("%s must be between 1 and 65535", name))
+		}
+	}
+	checkPort("db.port", c.DBPort, false)
+	checkPort("api.port", c.APIPort, false)
+	checkPort("api.tls.redirect_port", c.TLSRedirectPort, true)
+	checkPort("grpc.port", c.GRPCPort, true)
+	if c.GRPCPort != 0 && (c.GRPCPort == c.APIPort || c.GRPCPort == c.TLSRedirectPort) {
+		errs = append(errs, "grpc.port must differ from api.port and api.tls.redirect_port")
+	}
+
+	for key, d := range map[string]time.Duration{
+		"api.read_timeout":           c.APIReadTimeout,
+		"api.read_header_timeout":    c.APIReadHeaderTimeout,
+		"api.write_timeout":          c.APIWriteTimeout,
+		"api.idle_timeout":           c.APIIdleTimeout,
+		"api.shutdown_timeout":       c.APIShutdownTimeout,
+		"api.tls.reload_interval":    c.TLSReloadInterval,
+		"api.rate_limit.evict_after": c.RateLimitEvictAfter,
+	} {
+		if d < 0 {
+			errs = append(errs, key+" must not be negative")
+		}
+	}
+
+	if c.APIMaxHeaderBytes < 0 {
+		errs = append(errs, "api.max_header_bytes must not be negative")
+	}
+	if c.CompressionMinSize < 0 {
+		errs = append(errs, "api.compression.min_size must not be negative")
+	}
+	if c.CLIConfirmThreshold < 0 {
+		errs = append(errs, "cli.confirm_threshold must not be negative")
+	}
+
+	if c.RateLimitEnabled {
+		if c.RateLimitReadRate <= 0 || c.RateLimitWriteRate <= 0 {
+			errs = append(errs, "api.rate_limit.read_rate and api.rate_limit.write_rate must be positive")
+		}
+		if c.RateLimitReadBurst < 1 || c.RateLimitWriteBurst < 1 {
+			errs = append(errs, "api.rate_limit.read_burst and api.rate_limit.write_burst must be at least 1")
+		}
+	}
+
+	if c.CORSAllowCredentials {
+		for _, origin := range c.CORSAllowedOrigins {
+			if origin == "*" {
+				errs = append(errs, "api.cors.allow_credentials must not be set when api.cors.allowed_origins has *")
+				break
+			}
+		}
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, "api.tls.cert_file and api.tls.key_file must be set together")
+	}
+	if c.TLSCertFile == "" && (c.TLSClientCAFile != "" || c.TLSRedirectPort != 0) {
+		errs = append(errs, "api.tls.client_ca_file and api.tls.redirect_port require api.tls.cert_file")
+	}
+	if c.TLSRedirectPort != 0 && c.TLSRedirectPort == c.APIPort {
+		errs = append(errs, "api.tls.redirect_port must differ from api.port")
+	}
+
+	switch strings.ToLower(c.LogLevel) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		errs = append(errs, fmt.Sprintf("log.level %q must be one of debug, info, warn or error", c.LogLevel))
+	}
+
+	switch strings.ToLower(c.LogFormat) {
+	case "json", "text":
+	default:
+		errs = append(errs, fmt.Sprintf("log.format %q must be either json or text", c.LogFormat))
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return errors.New("invalid configuration: " + strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// ExportDatabaseEnv sets GOTODO_DB_* environment variables from c, as they
+// are read by gotodo/database package on its own.
+func (c *Config) ExportDatabaseEnv() error {
+	for env, value := range map[string]string{
+		"GOTODO_DB_HOST": c.DBHost,
+		"GOTODO_DB_PORT": strconv.Itoa(c.DBPort),
+		"GOTODO_DB_NAME": c.DBName,
+		"GOTODO_DB_USER": c.DBUser,
+		"GOTODO_DB_PASS": c.DBPass,
+	} {
+		if err := os.Setenv(env, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Print writes every setting of c along with where it is taken from. Secrets
+// are redacted.
+func (c *Config) Print(w io.Writer) error {
+	v := reflect.ValueOf(c).Elem()
+
+	for _, f := range fields() {
+		value := fmt.Sprint(v.Field(f.index).Interface())
+		if list, ok := v.Field(f.index).Interface().([]string); ok {
+			value = strings.Join(list, ",")
+		}
+		if f.secret && value != "" {
+			value = "********"
+		}
+
+		if _, err := fmt.Fprintf(w, "%s = %q # %s\n", f.key, value, c.sources[f.key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RegisterFlags defines a string flag for every setting on fs, e.g. --db-host
+// for "db.host". The returned function reports, by key, the flags which are
+// set once fs is parsed; it is meant to be used as Options.Flags.
+func RegisterFlags(fs *flag.FlagSet) func() map[string]string {
+	keys := map[string]string{}
+	for _, f := range fields() {
+		keys[f.flagKey] = f.key
+		fs.String(f.flagKey, "", fmt.Sprintf("overrides %s (%s)", f.key, f.env))
+	}
+
+	return func() map[string]string {
+		values := map[string]string{}
+		fs.Visit(func(fl *flag.Flag) {
+			if key, ok := keys[fl.Name]; ok {
+				values[key] = fl.Value.String()
+			}
+		})
+
+		return values
+	}
+}
+
+// readFile reads a configuration file into a flat map of settings by key.
+func readFile(file string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree map[string]interface{}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".toml":
+		t, err := toml.LoadBytes(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		tree = t.ToMap()
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(content, &tree); err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unknown configuration file format; use .toml, .yaml or .yml", file)
+	}
+
+	values := map[string]string{}
+	flatten("", tree, values)
+
+	return values, nil
+}
+
+func flatten(prefix string, node interface{}, values map[string]string) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for k, v := range n {
+			flatten(prefix+k+".", v, values)
+		}
+	case map[interface{}]interface{}:
+		for k, v := range n {
+			flatten(prefix+fmt.Sprint(k)+".", v, values)
+		}
+	case []interface{}:
+		items := make([]string, len(n))
+		for i, item := range n {
+			items[i] = fmt.Sprint(item)
+		}
+		values[strings.TrimSuffix(prefix, ".")] = strings.Join(items, ",")
+	case nil:
+	default:
+		values[strings.TrimSuffix(prefix, ".")] = fmt.Sprint(n)
+	}
+}
+
+// readEnvFile parses a .env file without applying it to the environment. A
+// missing file yields no values.
+func readEnvFile(file string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := gotenv.StrictParse(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+
+	return env, nil
+}
@@ -0,0 +1,143 @@
+package config_test
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/config"
+)
+
+func writeFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := config.Load(config.Options{EnvFile: filepath.Join(t.TempDir(), ".env")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", cfg.DBHost)
+	assert.Equal(t, 8080, cfg.APIPort)
+	assert.Equal(t, 15*time.Second, cfg.APIReadTimeout)
+	assert.Equal(t, "info", cfg.LogLevel)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	toml := writeFile(t, dir, "gotodo.toml", `
+[db]
+host = "file-host"
+name = "file-name"
+user = "file-user"
+port = 3307
+
+[api]
+port = 9000
+`)
+	envFile := writeFile(t, dir, ".env", "GOTODO_DB_NAME=dotenv-name\nGOTODO_DB_USER=dotenv-user\n")
+	t.Setenv("GOTODO_DB_USER", "env-user")
+	t.Setenv("GOTODO_API_PORT", "9001")
+	t.Setenv("GOTODO_API_CORS_ALLOWED_METHODS", "GET, POST")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := config.RegisterFlags(fs)
+	if err := fs.Parse([]string{"--api-port", "9002"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load(config.Options{File: toml, EnvFile: envFile, Flags: flags()})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "file-host", cfg.DBHost)
+	assert.Equal(t, 3307, cfg.DBPort)
+	assert.Equal(t, "dotenv-name", cfg.DBName)
+	assert.Equal(t, "env-user", cfg.DBUser)
+	assert.Equal(t, 9002, cfg.APIPort)
+	assert.Equal(t, []string{"GET", "POST"}, cfg.CORSAllowedMethods)
+}
+
+func TestLoadYAML(t *testing.T) {
+	dir := t.TempDir()
+	yaml := writeFile(t, dir, "gotodo.yaml", `
+api:
+  write_timeout: 1m
+  tls:
+    cert_file: cert.pem
+    key_file: key.pem
+  cors:
+    allowed_origins: [https://a.example.com, https://b.example.com]
+  rate_limit:
+    read_rate: 0.5
+log:
+  format: text
+`)
+
+	cfg, err := config.Load(config.Options{File: yaml, EnvFile: filepath.Join(dir, ".env")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, cfg.APIWriteTimeout)
+	assert.Equal(t, "cert.pem", cfg.TLSCertFile)
+	assert.Equal(t, "text", cfg.LogFormat)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.CORSAllowedOrigins)
+	assert.Equal(t, 0.5, cfg.RateLimitReadRate)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+
+	t.Run("bad value", func(t *testing.T) {
+		t.Setenv("GOTODO_API_PORT", "eighty")
+
+		_, err := config.Load(config.Options{EnvFile: envFile})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("inconsistent values", func(t *testing.T) {
+		t.Setenv("GOTODO_API_TLS_CERT_FILE", "cert.pem")
+		t.Setenv("GOTODO_LOG_LEVEL", "loud")
+		t.Setenv("GOTODO_API_CORS_ALLOWED_ORIGINS", "*")
+		t.Setenv("GOTODO_API_CORS_ALLOW_CREDENTIALS", "true")
+
+		_, err := config.Load(config.Options{EnvFile: envFile})
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "api.tls.key_file")
+			assert.Contains(t, err.Error(), "log.level")
+			assert.Contains(t, err.Error(), "api.cors.allow_credentials")
+		}
+	})
+
+	t.Run("unknown file format", func(t *testing.T) {
+		_, err := config.Load(config.Options{File: writeFile(t, dir, "gotodo.ini", ""), EnvFile: envFile})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestPrint(t *testing.T) {
+	t.Setenv("GOTODO_DB_PASS", "hunter2")
+
+	cfg, err := config.Load(config.Options{EnvFile: filepath.Join(t.TempDir(), ".env")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, cfg.Print(buf))
+
+	assert.Contains(t, buf.String(), `db.pass = "********" # env`)
+	assert.Contains(t, buf.String(), `db.host = "127.0.0.1" # default`)
+	assert.NotContains(t, buf.String(), "hunter2")
+}
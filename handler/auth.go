@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// publicRoutes are the routes served without a token by RequireToken, so that
+// load balancers and orchestrators can probe the server.
+var publicRoutes = map[string]bool{"/healthz": true, "/readyz": true}
+
+// tokenMatches tells whether authorization, the value of an Authorization
+// header, carries token as a bearer token.
+func tokenMatches(authorization, token string) bool {
+	const prefix = "Bearer "
+	if len(authorization) < len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(authorization[len(prefix):]), []byte(token)) == 1
+}
+
+// RequireToken returns a http.Handler which refuses the requests to next
+// which do not carry token as a bearer token, except those to "/healthz" and
+// "/readyz". If token is empty, every request is passed to next.
+func RequireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !publicRoutes[r.URL.Path] && !tokenMatches(r.Header.Get("Authorization"), token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gotodo"`)
+			respondWithErrorInJSON(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
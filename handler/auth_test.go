@@ -0,0 +1,48 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/handler"
+)
+
+func TestRequireToken(t *testing.T) {
+	h := handler.RequireToken("secret", handler.NewServer(newExampleService()))
+
+	for _, tc := range []struct {
+		name          string
+		path          string
+		authorization string
+		code          int
+	}{
+		{"no token", "/v1/", "", http.StatusUnauthorized},
+		{"wrong token", "/v1/", "Bearer guess", http.StatusUnauthorized},
+		{"not a bearer token", "/v1/", "Basic c2VjcmV0", http.StatusUnauthorized},
+		{"token", "/v1/", "Bearer secret", http.StatusOK},
+		{"lowercase scheme", "/v1/1", "bearer secret", http.StatusOK},
+		{"health probe", "/healthz", "", http.StatusOK},
+		{"readiness probe", "/readyz", "", http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.path, nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			rr := execute(h, req)
+
+			assert.Equal(t, tc.code, rr.Code)
+			if tc.code == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="gotodo"`, rr.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+
+	// Without a token, every request is served.
+	rr := execute(handler.RequireToken("", handler.NewServer(newExampleService())), httptest.NewRequest("GET", "/v1/", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Touch records that the Todos are modified now. The routes of s call it on
+// their own; other writers of the Todos, e.g. the gRPC server, must call it so
+// that clients do not get a stale 304 Not Modified.
+func (s *Server) Touch() {
+	s.modified.Store(time.Now().UnixNano())
+}
+
+// lastModified returns the time of the most recent modification of the Todos
+// seen by s, or the time s is created if there is none. As HTTP dates have a
+// precision of one second, it is rounded up to the next second.
+func (s *Server) lastModified() time.Time {
+	modified := time.Unix(0, s.modified.Load())
+	if rounded := modified.Truncate(time.Second); rounded.Before(modified) {
+		modified = rounded.Add(time.Second)
+	}
+
+	return modified.UTC()
+}
+
+// notModified adds caching headers to the response of a read. It answers 304
+// Not Modified and returns true if the client already has the current Todos,
+// as told by the If-Modified-Since header of r.
+func (s *Server) notModified(w http.ResponseWriter, r *http.Request) bool {
+	if s.CacheMaxAge > 0 {
+		w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(int(s.CacheMaxAge.Seconds())))
+	} else {
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
+
+	// Until the second of the last modification is over, another modification
+	// may happen with the same Last-Modified date.
+	modified := s.lastModified()
+	if modified.After(time.Now()) {
+		return false
+	}
+
+	w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.After(since) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// noStore marks the response of a write as not cacheable.
+func noStore(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "no-store")
+}
@@ -0,0 +1,133 @@
+package handler_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/handler"
+)
+
+func TestCompress(t *testing.T) {
+	decoders := map[string]func(r io.Reader) (io.Reader, error){
+		"gzip": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"br":   func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+	}
+
+	for _, tc := range []struct {
+		accept   string
+		encoding string
+	}{
+		{"gzip", "gzip"},
+		{"gzip, br", "br"},
+		{"br;q=0.5, gzip", "gzip"},
+		{"*", "br"},
+	} {
+		t.Run(tc.accept, func(t *testing.T) {
+			h := handler.Compress(1, handler.NewServer(newExampleService()))
+
+			req := httptest.NewRequest("GET", "/v1/", nil)
+			req.Header.Set("Accept-Encoding", tc.accept)
+			rr := execute(h, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, tc.encoding, rr.Header().Get("Content-Encoding"))
+			assert.Contains(t, rr.Header()["Vary"], "Accept-Encoding")
+
+			body, err := decoders[tc.encoding](rr.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var todos []map[string]interface{}
+			assert.NoError(t, json.NewDecoder(body).Decode(&todos))
+			assert.NotEmpty(t, todos)
+		})
+	}
+
+	t.Run("below threshold", func(t *testing.T) {
+		h := handler.Compress(1<<20, handler.NewServer(newExampleService()))
+
+		req := httptest.NewRequest("GET", "/v1/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rr := execute(h, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.True(t, json.Valid(rr.Body.Bytes()))
+	})
+
+	t.Run("not JSON", func(t *testing.T) {
+		h := handler.Compress(1, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("plain text"))
+		}))
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rr := execute(h, req)
+
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, "plain text", rr.Body.String())
+	})
+
+	t.Run("not accepted", func(t *testing.T) {
+		h := handler.Compress(1, handler.NewServer(newExampleService()))
+
+		req := httptest.NewRequest("GET", "/v1/", nil)
+		req.Header.Set("Accept-Encoding", "identity, gzip;q=0")
+		rr := execute(h, req)
+
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		body, _ := ioutil.ReadAll(rr.Body)
+		assert.True(t, json.Valid(body))
+	})
+}
+
+func TestConditionalCaching(t *testing.T) {
+	svc := newExampleService()
+	h := handler.NewServer(svc)
+
+	// Last-Modified is only sent once the second of the last modification is
+	// over.
+	time.Sleep(time.Second)
+
+	rr := execute(h, httptest.NewRequest("GET", "/v1/", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "private, no-cache", rr.Header().Get("Cache-Control"))
+	modified := rr.Header().Get("Last-Modified")
+	if !assert.NotEmpty(t, modified) {
+		return
+	}
+
+	t.Run("not modified", func(t *testing.T) {
+		svc.GetAllInvoked = 0
+
+		req := httptest.NewRequest("GET", "/v1/", nil)
+		req.Header.Set("If-Modified-Since", modified)
+		rr := execute(h, req)
+
+		assert.Equal(t, http.StatusNotModified, rr.Code)
+		assert.Empty(t, rr.Body.String())
+		assert.Equal(t, 0, svc.GetAllInvoked)
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		rr := execute(h, httptest.NewRequest("PUT", "/v1/1/done", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+
+		req := httptest.NewRequest("GET", "/v1/1", nil)
+		req.Header.Set("If-Modified-Since", modified)
+		rr = execute(h, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+}
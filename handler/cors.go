@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions configures CORS.
+type CORSOptions struct {
+	// AllowedOrigins are the origins allowed to call the API, e.g.
+	// "https://app.example.com". "*" allows any origin.
+	AllowedOrigins []string
+
+	// AllowedMethods are the methods allowed in cross-origin requests. They
+	// default to the methods of the routes registered in NewServer.
+	AllowedMethods []string
+
+	// AllowedHeaders are the request headers allowed in cross-origin requests,
+	// besides the CORS-safelisted ones. They default to Content-Type, Accept
+	// and X-Request-ID.
+	AllowedHeaders []string
+
+	// ExposedHeaders are the response headers readable by browser clients,
+	// besides the CORS-safelisted ones.
+	ExposedHeaders []string
+
+	// AllowCredentials allows cross-origin requests to carry cookies and
+	// client certificates. It is ignored if AllowedOrigins has "*", as any
+	// site could then make credentialed requests.
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache the result of a preflight request.
+	MaxAge time.Duration
+}
+
+type cors struct {
+	opts    CORSOptions
+	anyOrig bool
+	origins map[string]bool
+	methods string
+	headers map[string]bool
+	next    http.Handler
+}
+
+// CORS returns a http.Handler which adds CORS headers to the responses of
+// next, and answers preflight requests on its behalf.
+func CORS(opts CORSOptions, next http.Handler) http.Handler {
+	if len(opts.AllowedMethods) == 0 {
+		opts.AllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	}
+	if len(opts.AllowedHeaders) == 0 {
+		opts.AllowedHeaders = []string{"Content-Type", "Accept", RequestIDHeader}
+	}
+
+	c := &cors{
+		opts:    opts,
+		origins: map[string]bool{},
+		headers: map[string]bool{},
+		next:    next,
+	}
+
+	for _, origin := range opts.AllowedOrigins {
+		if origin == "*" {
+			c.anyOrig = true
+		}
+		c.origins[strings.ToLower(origin)] = true
+	}
+
+	if c.anyOrig {
+		c.opts.AllowCredentials = false
+	}
+
+	// opts.AllowedMethods belongs to the caller.
+	c.opts.AllowedMethods = make([]string, len(opts.AllowedMethods))
+	for i, method := range opts.AllowedMethods {
+		c.opts.AllowedMethods[i] = strings.ToUpper(method)
+	}
+	c.methods = strings.Join(c.opts.AllowedMethods, ", ")
+
+	for _, header := range opts.AllowedHeaders {
+		c.headers[http.CanonicalHeaderKey(header)] = true
+	}
+
+	return c
+}
+
+func (c *cors) originAllowed(origin string) bool {
+	return c.anyOrig || c.origins[strings.ToLower(origin)]
+}
+
+func (c *cors) methodAllowed(method string) bool {
+	for _, m := range c.opts.AllowedMethods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *cors) headersAllowed(headers string) bool {
+	for _, header := range strings.Split(headers, ",") {
+		header = strings.TrimSpace(header)
+		if header != "" && !c.headers[http.CanonicalHeaderKey(header)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *cors) setOrigin(w http.ResponseWriter, origin string) {
+	if c.anyOrig {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+
+	if c.opts.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (c *cors) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	w.Header().Add("Vary", "Origin")
+
+	if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "" {
+		c.preflight(w, r, origin)
+		return
+	}
+
+	if origin != "" && c.originAllowed(origin) {
+		c.setOrigin(w, origin)
+		if len(c.opts.ExposedHeaders) > 0 {
+			w.Header().Set("Access-Control-Expose-Headers", strings.Join(c.opts.ExposedHeaders, ", "))
+		}
+	}
+
+	c.next.ServeHTTP(w, r)
+}
+
+// preflight answers a preflight request. A request which is not allowed is
+// answered without CORS headers, so that the browser refuses to send the actual
+// request.
+func (c *cors) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+	headers := r.Header.Get("Access-Control-Request-Headers")
+
+	if origin == "" || !c.originAllowed(origin) || !c.methodAllowed(method) || !c.headersAllowed(headers) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	c.setOrigin(w, origin)
+	w.Header().Set("Access-Control-Allow-Methods", c.methods)
+	if headers != "" {
+		w.Header().Set("Access-Control-Allow-Headers", headers)
+	}
+	if c.opts.MaxAge > 0 {
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(c.opts.MaxAge.Seconds())))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
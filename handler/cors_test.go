@@ -0,0 +1,108 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/handler"
+)
+
+func preflight(target string, origin string, method string, headers string) *http.Request {
+	req := httptest.NewRequest("OPTIONS", target, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+
+	return req
+}
+
+func TestCORS(t *testing.T) {
+	svc := newExampleService()
+	h := handler.CORS(handler.CORSOptions{
+		AllowedOrigins: []string{"https://app.example.com"},
+		ExposedHeaders: []string{handler.RequestIDHeader},
+		MaxAge:         10 * time.Minute,
+	}, handler.NewServer(svc))
+
+	t.Run("preflight on every route", func(t *testing.T) {
+		routes := []struct {
+			target string
+			method string
+		}{
+			{"/v1/", "GET"},
+			{"/v1/", "POST"},
+			{"/v1/", "DELETE"},
+			{"/v1/1", "GET"},
+			{"/v1/1", "PATCH"},
+			{"/v1/1", "DELETE"},
+			{"/v1/1/done", "PUT"},
+		}
+
+		for _, route := range routes {
+			rr := execute(h, preflight(route.target, "https://app.example.com", route.method, "content-type"))
+
+			assert.Equal(t, http.StatusNoContent, rr.Code, route.method+" "+route.target)
+			assert.Equal(t, "https://app.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+			assert.Contains(t, rr.Header().Get("Access-Control-Allow-Methods"), route.method)
+			assert.Equal(t, "content-type", rr.Header().Get("Access-Control-Allow-Headers"))
+			assert.Equal(t, "600", rr.Header().Get("Access-Control-Max-Age"))
+		}
+	})
+
+	t.Run("preflight from unknown origin", func(t *testing.T) {
+		rr := execute(h, preflight("/v1/", "https://evil.example.com", "POST", ""))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("preflight with unknown header", func(t *testing.T) {
+		rr := execute(h, preflight("/v1/", "https://app.example.com", "POST", "X-Secret"))
+
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("actual request", func(t *testing.T) {
+		svc.GetAllInvoked = 0
+
+		req := httptest.NewRequest("GET", "/v1/", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		rr := execute(h, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, 1, svc.GetAllInvoked)
+		assert.Equal(t, "https://app.example.com", rr.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, handler.RequestIDHeader, rr.Header().Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("any origin with credentials", func(t *testing.T) {
+		h := handler.CORS(handler.CORSOptions{
+			AllowedOrigins:   []string{"*"},
+			AllowCredentials: true,
+		}, handler.NewServer(svc))
+
+		rr := execute(h, preflight("/v1/", "https://other.example.com", "GET", ""))
+
+		assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, rr.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("options are not modified", func(t *testing.T) {
+		methods := []string{"get", "post"}
+		h := handler.CORS(handler.CORSOptions{
+			AllowedOrigins: []string{"https://app.example.com"},
+			AllowedMethods: methods,
+		}, handler.NewServer(svc))
+
+		rr := execute(h, preflight("/v1/", "https://app.example.com", "POST", ""))
+
+		assert.Equal(t, "GET, POST", rr.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, []string{"get", "post"}, methods)
+	})
+}
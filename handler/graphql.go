@@ -0,0 +1,356 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+type graphQLKey struct{}
+
+// graphQLRequest is what GraphQL resolvers need from the HTTP request they are
+// resolving for.
+type graphQLRequest struct {
+	server  *Server
+	service gotodo.Service
+}
+
+func graphQLRequestOf(p graphql.ResolveParams) *graphQLRequest {
+	return p.Context.Value(graphQLKey{}).(*graphQLRequest)
+}
+
+// graphQLTodoField resolves a field of a Todo from the *gotodo.Todo it is given.
+func graphQLTodoField(typ graphql.Output, get func(todo *gotodo.Todo) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewNonNull(typ),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(*gotodo.Todo)), nil
+		},
+	}
+}
+
+var graphQLTodo = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Todo",
+	Description: "A thing to do.",
+	Fields: graphql.Fields{
+		"id":          graphQLTodoField(graphql.Int, func(todo *gotodo.Todo) interface{} { return todo.ID }),
+		"title":       graphQLTodoField(graphql.String, func(todo *gotodo.Todo) interface{} { return todo.Title }),
+		"description": graphQLTodoField(graphql.String, func(todo *gotodo.Todo) interface{} { return todo.Description }),
+		"done":        graphQLTodoField(graphql.Boolean, func(todo *gotodo.Todo) interface{} { return todo.Done }),
+	},
+})
+
+// graphQLGet returns the Todo whose ID is the "id" argument of p.
+func graphQLGet(p graphql.ResolveParams) (*gotodo.Todo, error) {
+	todo, err := graphQLRequestOf(p).service.Get(p.Args["id"].(int))
+	if err != nil {
+		return nil, errors.New("Todo not found")
+	}
+
+	return todo, nil
+}
+
+var graphQLQuery = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Query",
+	Fields: graphql.Fields{
+		"todo": &graphql.Field{
+			Type:        graphQLTodo,
+			Description: "Get a Todo.",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return graphQLGet(p)
+			},
+		},
+		"todos": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLTodo))),
+			Description: "Get Todos, optionally filtered by their done state, a page at a time.",
+			Args: graphql.FieldConfigArgument{
+				"done": &graphql.ArgumentConfig{
+					Type:        graphql.Boolean,
+					Description: "Only get finished Todos if true, or pending Todos if false.",
+				},
+				"first": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: "Get at most this many Todos.",
+				},
+				"after": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: "Only get the Todos listed after the Todo with this ID.",
+				},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				svc := graphQLRequestOf(p).service
+
+				var todos []*gotodo.Todo
+				switch done, ok := p.Args["done"].(bool); {
+				case !ok:
+					todos = svc.GetAll()
+				case done:
+					todos = svc.GetFinished()
+				default:
+					todos = svc.GetPending()
+				}
+
+				if after, ok := p.Args["after"].(int); ok {
+					i := 0
+					for i < len(todos) && todos[i].ID != after {
+						i++
+					}
+					if i == len(todos) {
+						return nil, fmt.Errorf("Todo %d is not listed", after)
+					}
+					todos = todos[i+1:]
+				}
+
+				if first, ok := p.Args["first"].(int); ok {
+					if first < 0 {
+						return nil, errors.New("first must not be negative")
+					}
+					if first < len(todos) {
+						todos = todos[:first]
+					}
+				}
+
+				return todos, nil
+			},
+		},
+	},
+})
+
+var graphQLMutation = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Mutation",
+	Fields: graphql.Fields{
+		"addTodo": &graphql.Field{
+			Type:        graphQLTodo,
+			Description: "Create a Todo.",
+			Args: graphql.FieldConfigArgument{
+				"title":       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				"description": &graphql.ArgumentConfig{Type: graphql.String},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				req := graphQLRequestOf(p)
+				description, _ := p.Args["description"].(string)
+
+				todo, err := req.service.Add(p.Args["title"].(string), description)
+				if err != nil {
+					return nil, err
+				}
+				req.server.Touch()
+
+				return todo, nil
+			},
+		},
+		"editTodo": &graphql.Field{
+			Type:        graphQLTodo,
+			Description: "Edit the title and/or the description of a Todo.",
+			Args: graphql.FieldConfigArgument{
+				"id":          &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				"title":       &graphql.ArgumentConfig{Type: graphql.String},
+				"description": &graphql.ArgumentConfig{Type: graphql.String},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				req := graphQLRequestOf(p)
+
+				todo, err := graphQLGet(p)
+				if err != nil {
+					return nil, err
+				}
+				if title, ok := p.Args["title"].(string); ok {
+					todo.Title = title
+				}
+				if description, ok := p.Args["description"].(string); ok {
+					todo.Description = description
+				}
+
+				if err := req.service.Edit(todo); err != nil {
+					return nil, err
+				}
+				req.server.Touch()
+
+				return todo, nil
+			},
+		},
+		"markTodoDone": &graphql.Field{
+			Type:        graphQLTodo,
+			Description: "Mark a Todo as done.",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				req := graphQLRequestOf(p)
+
+				todo, err := graphQLGet(p)
+				if err != nil {
+					return nil, err
+				}
+
+				if err := req.service.MarkAsDone(todo); err != nil {
+					return nil, err
+				}
+				req.server.Touch()
+
+				return todo, nil
+			},
+		},
+		"deleteTodo": &graphql.Field{
+			Type:        graphql.Boolean,
+			Description: "Delete a Todo.",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				req := graphQLRequestOf(p)
+
+				todo, err := graphQLGet(p)
+				if err != nil {
+					return nil, err
+				}
+
+				if err := req.service.Delete(todo); err != nil {
+					return nil, err
+				}
+				req.server.Touch()
+
+				return true, nil
+			},
+		},
+		"deleteFinished": &graphql.Field{
+			Type:        graphql.Boolean,
+			Description: "Delete all finished Todos.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				req := graphQLRequestOf(p)
+
+				req.service.DeleteFinished()
+				req.server.Touch()
+
+				return true, nil
+			},
+		},
+	},
+})
+
+var graphQLSchema = func() graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:    graphQLQuery,
+		Mutation: graphQLMutation,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return schema
+}()
+
+// graphQLParams is a GraphQL request, as sent over HTTP.
+type graphQLParams struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQL is a handler for "/graphql" route. It runs GraphQL queries and
+// mutations on the Todos.
+//
+// A GET request carries its query in the "query", "operationName" and
+// "variables" query strings; it can not run mutations. A POST request carries
+// it as a JSON object, or a JSON array of them to run a batch.
+func (s *Server) GraphQL(w http.ResponseWriter, r *http.Request) {
+	var batch []graphQLParams
+	batched := false
+
+	switch r.Method {
+	case "GET":
+		params := graphQLParams{
+			Query:         r.URL.Query().Get("query"),
+			OperationName: r.URL.Query().Get("operationName"),
+		}
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &params.Variables); err != nil {
+				respondWithErrorInJSON(w, http.StatusBadRequest, errors.New("Invalid variables"))
+				return
+			}
+		}
+		batch = append(batch, params)
+	case "POST":
+		defer r.Body.Close()
+
+		var payload json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			respondWithErrorInJSON(w, http.StatusBadRequest, errors.New("Invalid request payload"))
+			return
+		}
+
+		batched = strings.HasPrefix(strings.TrimSpace(string(payload)), "[")
+		if !batched {
+			payload = json.RawMessage("[" + string(payload) + "]")
+		}
+		if err := json.Unmarshal(payload, &batch); err != nil {
+			respondWithErrorInJSON(w, http.StatusBadRequest, errors.New("Invalid request payload"))
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		respondWithErrorInJSON(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), graphQLKey{}, &graphQLRequest{server: s, service: s.service(r)})
+
+	results := make([]*graphql.Result, len(batch))
+	for i, params := range batch {
+		if r.Method == "GET" && isGraphQLMutation(params) {
+			w.Header().Set("Allow", "POST")
+			respondWithErrorInJSON(w, http.StatusMethodNotAllowed, errors.New("mutations must be sent with POST"))
+			return
+		}
+
+		results[i] = graphql.Do(graphql.Params{
+			Schema:         graphQLSchema,
+			RequestString:  params.Query,
+			OperationName:  params.OperationName,
+			VariableValues: params.Variables,
+			Context:        ctx,
+		})
+	}
+
+	if batched {
+		respondInJSON(w, http.StatusOK, results)
+	} else {
+		respondInJSON(w, http.StatusOK, results[0])
+	}
+}
+
+// isGraphQLMutation tells whether params runs a mutation. A query which can not
+// be parsed is left for graphql.Do to report.
+func isGraphQLMutation(params graphQLParams) bool {
+	doc, err := parser.Parse(parser.ParseParams{Source: params.Query})
+	if err != nil {
+		return false
+	}
+
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if params.OperationName != "" && (op.Name == nil || op.Name.Value != params.OperationName) {
+			continue
+		}
+		if op.Operation == ast.OperationTypeMutation {
+			return true
+		}
+	}
+
+	return false
+}
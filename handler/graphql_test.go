@@ -0,0 +1,186 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/handler"
+)
+
+func postGraphQL(t *testing.T, h http.Handler, payload interface{}) *httptest.ResponseRecorder {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return execute(h, httptest.NewRequest("POST", "/graphql", bytes.NewBuffer(body)))
+}
+
+type graphQLResult struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func decodeGraphQL(t *testing.T, rr *httptest.ResponseRecorder) graphQLResult {
+	var result graphQLResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
+
+func TestGraphQLIntrospection(t *testing.T) {
+	h := handler.NewServer(newExampleService())
+
+	rr := postGraphQL(t, h, map[string]string{"query": `{
+		__schema {
+			queryType { fields { name } }
+			mutationType { fields { name } }
+		}
+		__type(name: "Todo") { fields { name } }
+	}`})
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var result struct {
+		Data struct {
+			Schema struct {
+				QueryType    struct{ Fields []struct{ Name string } }
+				MutationType struct{ Fields []struct{ Name string } }
+			} `json:"__schema"`
+			Type struct{ Fields []struct{ Name string } } `json:"__type"`
+		}
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(fields []struct{ Name string }) []string {
+		list := []string{}
+		for _, f := range fields {
+			list = append(list, f.Name)
+		}
+		sort.Strings(list)
+		return list
+	}
+
+	assert.Equal(t, []string{"todo", "todos"}, names(result.Data.Schema.QueryType.Fields))
+	assert.Equal(t, []string{"addTodo", "deleteFinished", "deleteTodo", "editTodo", "markTodoDone"},
+		names(result.Data.Schema.MutationType.Fields))
+	assert.Equal(t, []string{"description", "done", "id", "title"}, names(result.Data.Type.Fields))
+}
+
+func TestGraphQLQueries(t *testing.T) {
+	svc := newExampleService()
+	svc.GetAllFn = func() []*gotodo.Todo {
+		return []*gotodo.Todo{{ID: 1, Title: "One"}, {ID: 2, Title: "Two"}, {ID: 3, Title: "Three"}}
+	}
+	h := handler.NewServer(svc)
+
+	t.Run("todo", func(t *testing.T) {
+		rr := postGraphQL(t, h, map[string]interface{}{
+			"query":     `query ($id: Int!) { todo(id: $id) { id title } }`,
+			"variables": map[string]interface{}{"id": 1},
+		})
+
+		result := decodeGraphQL(t, rr)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, map[string]interface{}{"id": 1.0, "title": "Buy milk"}, result.Data["todo"])
+	})
+
+	t.Run("todos page", func(t *testing.T) {
+		rr := postGraphQL(t, h, map[string]string{"query": `{ todos(first: 1, after: 1) { id } }`})
+
+		result := decodeGraphQL(t, rr)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, []interface{}{map[string]interface{}{"id": 2.0}}, result.Data["todos"])
+	})
+
+	t.Run("todos by state", func(t *testing.T) {
+		svc.GetFinishedInvoked = 0
+
+		rr := postGraphQL(t, h, map[string]string{"query": `{ todos(done: true) { id } }`})
+
+		result := decodeGraphQL(t, rr)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, []interface{}{}, result.Data["todos"])
+		assert.Equal(t, 1, svc.GetFinishedInvoked)
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		rr := execute(h, httptest.NewRequest("GET", "/graphql?query="+url.QueryEscape(`{ todo(id: 1) { done } }`), nil))
+
+		result := decodeGraphQL(t, rr)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, map[string]interface{}{"done": false}, result.Data["todo"])
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		rr := postGraphQL(t, h, []map[string]string{
+			{"query": `{ todo(id: 1) { id } }`},
+			{"query": `{ todos { id } }`},
+		})
+
+		var results []graphQLResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &results); err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, results, 2)
+	})
+}
+
+func TestGraphQLMutations(t *testing.T) {
+	svc := newExampleService()
+	h := handler.NewServer(svc)
+
+	rr := postGraphQL(t, h, map[string]string{"query": `mutation {
+		addTodo(title: "Buy eggs", description: "A dozen") { title description }
+		editTodo(id: 1, title: "Buy bread") { title description }
+		markTodoDone(id: 1) { done }
+		deleteTodo(id: 1)
+		deleteFinished
+	}`})
+
+	result := decodeGraphQL(t, rr)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, map[string]interface{}{"title": "Buy eggs", "description": "A dozen"}, result.Data["addTodo"])
+	assert.Equal(t, map[string]interface{}{"title": "Buy bread", "description": "Two bottles"}, result.Data["editTodo"])
+	assert.Equal(t, map[string]interface{}{"done": true}, result.Data["markTodoDone"])
+	assert.Equal(t, true, result.Data["deleteTodo"])
+	assert.Equal(t, true, result.Data["deleteFinished"])
+	assert.Equal(t, 1, svc.AddInvoked)
+	assert.Equal(t, 1, svc.EditInvoked)
+	assert.Equal(t, 1, svc.MarkAsDoneInvoked)
+	assert.Equal(t, 1, svc.DeleteInvoked)
+	assert.Equal(t, 1, svc.DeleteFinishedInvoked)
+
+	t.Run("not found", func(t *testing.T) {
+		svc.GetFn = func(id int) (*gotodo.Todo, error) {
+			return nil, errors.New("not found")
+		}
+
+		rr := postGraphQL(t, h, map[string]string{"query": `mutation { deleteTodo(id: 2) }`})
+
+		result := decodeGraphQL(t, rr)
+		if assert.Len(t, result.Errors, 1) {
+			assert.Equal(t, "Todo not found", result.Errors[0].Message)
+		}
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		rr := execute(h, httptest.NewRequest("GET", "/graphql?query="+url.QueryEscape(`mutation { deleteFinished }`), nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
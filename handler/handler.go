@@ -5,10 +5,13 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"sync/atomic"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 
 	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/timestamps"
 )
 
 func respondWithErrorInJSON(w http.ResponseWriter, code int, err error) {
@@ -23,78 +26,185 @@ func respondInJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.Write(response)
 }
 
+// Server is a http.Handler which serves the Todo API. Router serves the current
+// API version under the "/v1" prefix; the unversioned routes are kept as a
+// deprecated alias to it.
 type Server struct {
 	Service gotodo.Service
 	Router  *httprouter.Router
+
+	// Probe is run by the "/readyz" route, with ProbeTimeout as its deadline.
+	Probe        Probe
+	ProbeTimeout time.Duration
+
+	// CacheMaxAge is how long clients may reuse the Todos they read before
+	// revalidating them. If zero, they revalidate on every use.
+	CacheMaxAge time.Duration
+
+	// Times, if set, tells the "/stats" route when the Todos were created and
+	// completed.
+	Times timestamps.Timed
+
+	mux    *http.ServeMux
+	legacy *httprouter.Router
+
+	// routes are the routes registered on s, as returned by Routes.
+	routes []string
+
+	// modified is the Unix time of the most recent modification of the Todos.
+	modified atomic.Int64
 }
 
 func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	s.Router.ServeHTTP(w, req)
+	s.mux.ServeHTTP(w, req)
 }
 
 func NewServer(svc gotodo.Service) *Server {
 	s := &Server{
 		Service: svc,
 		Router:  httprouter.New(),
-	}
+		mux:     http.NewServeMux(),
+		legacy:  httprouter.New(),
 
-	s.Router.GET("/", s.GetTodos)
-	s.Router.GET("/:id", s.Get)
-	s.Router.POST("/", s.Add)
-	s.Router.PATCH("/:id", s.Edit)
-	s.Router.PUT("/:id/done", s.MarkAsDone)
-	s.Router.DELETE("/:id", s.Delete)
-	s.Router.DELETE("/", s.DeleteFinished)
+		Probe:        ServiceProbe(svc),
+		ProbeTimeout: DefaultProbeTimeout,
+	}
+	s.Touch()
+
+	s.routeTodos(s.Router, "/"+currentVersion)
+	s.routeTodos(s.legacy, "")
+
+	// Routes which are not about Todos live on the mux, as httprouter does not
+	// allow them next to the "/:id" wildcard.
+	s.Handle("/openapi.json", http.HandlerFunc(s.OpenAPI))
+	s.Handle("/healthz", http.HandlerFunc(s.Healthz))
+	s.Handle("/readyz", http.HandlerFunc(s.Readyz))
+	s.Handle("/version", http.HandlerFunc(s.Version))
+	s.Handle("/graphql", http.HandlerFunc(s.GraphQL))
+	s.Handle("/stats", http.HandlerFunc(s.Stats))
+	s.mux.Handle("/"+currentVersion+"/", s.versioned())
+	s.mux.Handle("/", s.negotiated())
 
 	return s
 }
 
-// Get is a handler for GET "/:id" route. It will return a Todo with specified
+func (s *Server) routeTodos(router *httprouter.Router, prefix string) {
+	handle := func(method, path string, h httprouter.Handle) {
+		router.Handle(method, prefix+path, withRoute(prefix+path, h))
+		if router == s.Router {
+			s.routes = append(s.routes, method+" "+prefix+path)
+		}
+	}
+
+	handle("GET", "/", s.GetTodos)
+	handle("GET", "/:id", s.Get)
+	handle("POST", "/", s.Add)
+	handle("PATCH", "/:id", s.Edit)
+	handle("PUT", "/:id/done", s.MarkAsDone)
+	handle("DELETE", "/:id", s.Delete)
+	handle("DELETE", "/", s.DeleteFinished)
+}
+
+// Handle registers handler for a route outside of the Todo API, e.g.
+// "/metrics". pattern is interpreted as in http.ServeMux.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, withRouteFunc(pattern, handler))
+	s.routes = append(s.routes, pattern)
+}
+
+// Routes returns the routes registered on s: the Todo API routes as
+// "METHOD /v1/path", and the other routes as their pattern, whatever their
+// method. The deprecated unversioned aliases are left out.
+func (s *Server) Routes() []string {
+	return append([]string(nil), s.routes...)
+}
+
+// Get is a handler for GET "/v1/:id" route. It will return a Todo with specified
 // ID if exists. It will return an error otherwise.
 func (s *Server) Get(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if s.notModified(w, r) {
+		return
+	}
+
 	id, err := strconv.Atoi(ps.ByName("id"))
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusBadRequest, errors.New("cannot parse id"))
 		return
 	}
 
-	todo, err := s.Service.Get(id)
+	todo, err := s.service(r).Get(id)
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusNotFound, errors.New("Todo not found"))
 		return
 	}
 
-	respondInJSON(w, http.StatusOK, todo)
+	respondInJSON(w, http.StatusOK, s.withTimes(todo))
 }
 
-// GetTodos is a handler for GET "/" route. It will return an array of Todos.
+// GetTodos is a handler for GET "/v1/" route. It will return an array of Todos.
 //
 // A query string called "done" can also exist on the request. This query string
 // should be either "true" or "false". "true" means that user wants to get all
 // finished Todos; "false" otherwise.
+//
+// The "since" and "until" query strings select the Todos created within those
+// times, or updated or completed if the "timestamp" query string is "updated"
+// or "completed". They require Server.Times.
 func (s *Server) GetTodos(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if s.notModified(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	filter, err := timestamps.ParseFilter(query.Get("timestamp"), query.Get("since"), query.Get("until"), time.Now())
+	if err != nil {
+		respondWithErrorInJSON(w, http.StatusBadRequest, err)
+		return
+	}
+	if !filter.IsZero() && s.Times == nil {
+		respondWithErrorInJSON(w, http.StatusBadRequest, errors.New("timestamps of todos are not recorded"))
+		return
+	}
+
 	var todos []*gotodo.Todo
 
 	done, ok := r.URL.Query()["done"]
 	if ok && len(done[0]) > 0 {
 		if done[0] == "true" {
-			todos = s.Service.GetFinished()
+			todos = s.service(r).GetFinished()
 		} else {
-			todos = s.Service.GetPending()
+			todos = s.service(r).GetPending()
 		}
 	} else {
-		todos = s.Service.GetAll()
+		todos = s.service(r).GetAll()
+	}
+
+	if s.Times == nil {
+		respondInJSON(w, http.StatusOK, todos)
+		return
+	}
+
+	times, err := s.Times.Times()
+	if err != nil && filter.IsZero() {
+		respondInJSON(w, http.StatusOK, todos)
+		return
+	}
+	if err != nil {
+		respondWithErrorInJSON(w, http.StatusInternalServerError, err)
+		return
 	}
 
-	respondInJSON(w, http.StatusOK, todos)
+	respondInJSON(w, http.StatusOK, listWithTimes(filter.Apply(todos, times), times))
 }
 
-// Add is a handler for POST "/" route. It receives a JSON which corresponds to
+// Add is a handler for POST "/v1/" route. It receives a JSON which corresponds to
 // a Todo structure, adds the Todo using gotodo.Service, and returns the JSON
 // from the gotodo.Service. It returns an error if such error occurs.
 //
 // Add will only respect .title and .description from the JSON request body.
 func (s *Server) Add(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	noStore(w)
+
 	defer r.Body.Close()
 
 	todo := &gotodo.Todo{}
@@ -103,16 +213,17 @@ func (s *Server) Add(w http.ResponseWriter, r *http.Request, ps httprouter.Param
 		return
 	}
 
-	todo, err := s.Service.Add(todo.Title, todo.Description)
+	todo, err := s.service(r).Add(todo.Title, todo.Description)
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusInternalServerError, err)
 		return
 	}
+	s.Touch()
 
-	respondInJSON(w, http.StatusCreated, todo)
+	respondInJSON(w, http.StatusCreated, s.withTimes(todo))
 }
 
-// Edit is a handler for PATCH "/:id" route to Edit a Todo. It receives a JSON
+// Edit is a handler for PATCH "/v1/:id" route to Edit a Todo. It receives a JSON
 // which corresponds a Todo structure, applies the new values to the old values
 // using gotodo.Service, and returns back the Todo from the service. It returns
 // an error if such error occurs.
@@ -120,13 +231,15 @@ func (s *Server) Add(w http.ResponseWriter, r *http.Request, ps httprouter.Param
 // It will only respect .title and .description attribute, as .done is modified
 // only through MarkAsDone, as the gotodo package requests.
 func (s *Server) Edit(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	noStore(w)
+
 	id, err := strconv.Atoi(ps.ByName("id"))
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusBadRequest, errors.New("cannot parse id"))
 		return
 	}
 
-	todo, err := s.Service.Get(id)
+	todo, err := s.service(r).Get(id)
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusNotFound, errors.New("Todo not found"))
 		return
@@ -148,75 +261,85 @@ func (s *Server) Edit(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 		todo.Description = *todoEdit.Description
 	}
 
-	err = s.Service.Edit(todo)
+	err = s.service(r).Edit(todo)
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusInternalServerError, err)
 		return
 	}
+	s.Touch()
 
-	respondInJSON(w, http.StatusOK, todo)
+	respondInJSON(w, http.StatusOK, s.withTimes(todo))
 }
 
-// MarkAsDone is a handler for PUT "/:id/done" route to mark a Todo as done.
+// MarkAsDone is a handler for PUT "/v1/:id/done" route to mark a Todo as done.
 // It receives an empty request and returns the marked Todo from the service,
 // or an error if such error exists.
 func (s *Server) MarkAsDone(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	noStore(w)
+
 	id, err := strconv.Atoi(ps.ByName("id"))
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusBadRequest, errors.New("cannot parse id"))
 		return
 	}
 
-	todo, err := s.Service.Get(id)
+	todo, err := s.service(r).Get(id)
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusNotFound, errors.New("Todo not found"))
 		return
 	}
 
-	err = s.Service.MarkAsDone(todo)
+	err = s.service(r).MarkAsDone(todo)
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusInternalServerError, err)
 		return
 	}
+	s.Touch()
 
-	respondInJSON(w, http.StatusOK, todo)
+	respondInJSON(w, http.StatusOK, s.withTimes(todo))
 }
 
-// Delete is a handler for DELETE "/:id" route to Delete a Todo. It will return
+// Delete is a handler for DELETE "/v1/:id" route to Delete a Todo. It will return
 // an error if such error exists.
 func (s *Server) Delete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	noStore(w)
+
 	id, err := strconv.Atoi(ps.ByName("id"))
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusBadRequest, errors.New("cannot parse id"))
 		return
 	}
 
-	todo, err := s.Service.Get(id)
+	todo, err := s.service(r).Get(id)
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusNotFound, errors.New("Todo not found"))
 		return
 	}
 
-	err = s.Service.Delete(todo)
+	err = s.service(r).Delete(todo)
 	if err != nil {
 		respondWithErrorInJSON(w, http.StatusInternalServerError, err)
 		return
 	}
+	s.Touch()
 
 	w.WriteHeader(200)
 }
 
-// DeleteFinished is a handler for DELETE "/" route to delete all finished
+// DeleteFinished is a handler for DELETE "/v1/" route to delete all finished
 // Todos. It must receive a "done" query string with "true" value; otherwise,
 // it will return an error message.
 func (s *Server) DeleteFinished(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	noStore(w)
+
 	done, ok := r.URL.Query()["done"]
 	if !ok || done[0] != "true" {
 		respondWithErrorInJSON(w, http.StatusBadRequest, errors.New("?done=true should be set"))
 		return
 	}
 
-	s.Service.DeleteFinished()
+	s.service(r).DeleteFinished()
+	s.Touch()
 
 	w.WriteHeader(200)
 }
@@ -13,7 +13,7 @@ import (
 	"github.com/saifulwebid/gotodoapp/handler"
 )
 
-func execute(h *handler.Server, req *http.Request) *httptest.ResponseRecorder {
+func execute(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
 	rr := httptest.NewRecorder()
 	h.ServeHTTP(rr, req)
 
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/saifulwebid/gotodo"
+
+	"github.com/saifulwebid/gotodoapp/version"
+)
+
+// DefaultProbeTimeout is the time Readyz waits for Server.Probe to finish.
+const DefaultProbeTimeout = 2 * time.Second
+
+// Probe checks whether the storage behind a gotodo.Service is reachable.
+type Probe func() error
+
+// Pinger is implemented by gotodo.Service backends which can check their
+// connection to the storage.
+type Pinger interface {
+	Ping() error
+}
+
+// ServiceProbe returns a Probe for svc. If svc implements Pinger, the probe
+// pings it; otherwise the probe queries svc for its pending Todos, as
+// gotodo.Service has no way to report a storage error on its own.
+func ServiceProbe(svc gotodo.Service) Probe {
+	if p, ok := svc.(Pinger); ok {
+		return p.Ping
+	}
+
+	return func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+			}
+		}()
+
+		svc.GetPending()
+
+		return nil
+	}
+}
+
+func allowGetOnly(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		respondWithErrorInJSON(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return false
+	}
+
+	return true
+}
+
+// Healthz is a handler for GET "/healthz" route. It reports that the process
+// is alive.
+func (s *Server) Healthz(w http.ResponseWriter, r *http.Request) {
+	if !allowGetOnly(w, r) {
+		return
+	}
+
+	respondInJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Readyz is a handler for GET "/readyz" route. It runs Server.Probe and
+// reports whether the storage is reachable. It responds with 503 if the probe
+// fails or does not finish within Server.ProbeTimeout.
+func (s *Server) Readyz(w http.ResponseWriter, r *http.Request) {
+	if !allowGetOnly(w, r) {
+		return
+	}
+
+	timeout := s.ProbeTimeout
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+
+	// The probe keeps running after a timeout, so it must not read s.
+	probe := s.Probe
+	result := make(chan error, 1)
+	go func() {
+		result <- probe()
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-time.After(timeout):
+		err = errors.New("storage probe timed out")
+	}
+
+	if err != nil {
+		respondInJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"status": "unavailable",
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	respondInJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Version is a handler for GET "/version" route. It returns the build
+// information of the server.
+func (s *Server) Version(w http.ResponseWriter, r *http.Request) {
+	if !allowGetOnly(w, r) {
+		return
+	}
+
+	respondInJSON(w, http.StatusOK, version.Get())
+}
@@ -0,0 +1,89 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/handler"
+)
+
+func TestHealthz(t *testing.T) {
+	h := handler.NewServer(newExampleService())
+
+	rr := execute(h, httptest.NewRequest("GET", "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestReadyz(t *testing.T) {
+	h := handler.NewServer(newExampleService())
+
+	t.Run("ready", func(t *testing.T) {
+		h.Probe = func() error { return nil }
+
+		rr := execute(h, httptest.NewRequest("GET", "/readyz", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		h.Probe = func() error { return errors.New("connection refused") }
+
+		rr := execute(h, httptest.NewRequest("GET", "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		assert.Contains(t, rr.Body.String(), "connection refused")
+	})
+
+	t.Run("storage timeout", func(t *testing.T) {
+		release, probed := make(chan struct{}), make(chan struct{})
+		h.Probe = func() error {
+			defer close(probed)
+			<-release
+			return nil
+		}
+		h.ProbeTimeout = 10 * time.Millisecond
+
+		rr := execute(h, httptest.NewRequest("GET", "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+		// The probe which timed out must be done before the next subtest
+		// replaces it.
+		close(release)
+		<-probed
+	})
+
+	t.Run("service probe", func(t *testing.T) {
+		svc := newExampleService()
+		h.Probe = handler.ServiceProbe(svc)
+		h.ProbeTimeout = handler.DefaultProbeTimeout
+
+		rr := execute(h, httptest.NewRequest("GET", "/readyz", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, 1, svc.GetPendingInvoked)
+	})
+}
+
+func TestVersion(t *testing.T) {
+	h := handler.NewServer(newExampleService())
+
+	t.Run("get", func(t *testing.T) {
+		rr := execute(h, httptest.NewRequest("GET", "/version", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "go_version")
+	})
+
+	t.Run("wrong method", func(t *testing.T) {
+		rr := execute(h, httptest.NewRequest("POST", "/version", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+)
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying the identity of the user making the
+// request.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// User returns the identity of the user carried by ctx, or "" if the request
+// is anonymous.
+func User(ctx context.Context) string {
+	user, _ := ctx.Value(userKey{}).(string)
+	return user
+}
+
+// LoadClientIdentities reads a JSON file mapping client certificate subjects,
+// e.g. "CN=alice,O=Acme", to user identities.
+func LoadClientIdentities(file string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := map[string]string{}
+	if err := json.Unmarshal(content, &identities); err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// ClientCertIdentity returns a http.Handler which identifies the user of every
+// request served by next from the verified client certificate, if any.
+//
+// The subject of the certificate is looked up in identities, first as a whole,
+// then by its common name only (e.g. "CN=alice"). Requests with a certificate
+// whose subject is not in identities are refused. If identities is nil, the
+// common name of the certificate is used as the user identity.
+func ClientCertIdentity(identities map[string]string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		subject := r.TLS.PeerCertificates[0].Subject
+
+		user := subject.CommonName
+		if identities != nil {
+			var ok bool
+			user, ok = identities[subject.String()]
+			if !ok {
+				user, ok = identities["CN="+subject.CommonName]
+			}
+
+			if !ok {
+				respondWithErrorInJSON(w, http.StatusForbidden, errors.New("unknown client certificate"))
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+	})
+}
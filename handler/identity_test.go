@@ -0,0 +1,78 @@
+package handler_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/handler"
+)
+
+func TestClientCertIdentity(t *testing.T) {
+	var user string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = handler.User(r.Context())
+	})
+
+	requestWithCert := func(subject pkix.Name) *http.Request {
+		req := httptest.NewRequest("GET", "/v1/", nil)
+		req.TLS = &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: subject}},
+		}
+
+		return req
+	}
+
+	t.Run("anonymous", func(t *testing.T) {
+		user = "-"
+		h := handler.ClientCertIdentity(nil, next)
+
+		rr := execute(h, httptest.NewRequest("GET", "/v1/", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "", user)
+	})
+
+	t.Run("common name", func(t *testing.T) {
+		h := handler.ClientCertIdentity(nil, next)
+
+		rr := execute(h, requestWithCert(pkix.Name{CommonName: "alice"}))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "alice", user)
+	})
+
+	identities := map[string]string{
+		"CN=alice,O=Acme": "alice@acme",
+		"CN=bob":          "bob",
+	}
+	h := handler.ClientCertIdentity(identities, next)
+
+	t.Run("mapped subject", func(t *testing.T) {
+		rr := execute(h, requestWithCert(pkix.Name{CommonName: "alice", Organization: []string{"Acme"}}))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "alice@acme", user)
+	})
+
+	t.Run("mapped common name", func(t *testing.T) {
+		rr := execute(h, requestWithCert(pkix.Name{CommonName: "bob", Organization: []string{"Acme"}}))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "bob", user)
+	})
+
+	t.Run("unknown subject", func(t *testing.T) {
+		user = "-"
+
+		rr := execute(h, requestWithCert(pkix.Name{CommonName: "mallory"}))
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Equal(t, "-", user)
+	})
+}
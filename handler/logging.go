@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/saifulwebid/gotodo"
+
+	"github.com/saifulwebid/gotodoapp/logging"
+)
+
+// RequestIDHeader is the header carrying the ID of a request. An ID sent by the
+// client is kept; otherwise one is generated. The ID is sent back in the
+// response.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// LogRequests returns a http.Handler which assigns an ID to every request
+// served by next, and writes an access log entry to logger once the request is
+// served.
+func LogRequests(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		r = r.WithContext(logging.WithRequestID(r.Context(), id))
+		r, route := trackRoute(r)
+		rec := newResponseRecorder(w)
+		start := time.Now()
+
+		next.ServeHTTP(rec, r)
+
+		level := slog.LevelInfo
+		if rec.Status() >= 500 {
+			level = slog.LevelError
+		}
+
+		logger.LogAttrs(r.Context(), level, "request",
+			slog.String("request_id", id),
+			slog.String("method", r.Method),
+			slog.String("route", route.Route()),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.Status()),
+			slog.Duration("duration", time.Since(start)),
+			slog.Int("bytes", rec.bytes),
+			slog.String("remote_addr", r.RemoteAddr),
+		)
+	})
+}
+
+// contextBinder is implemented by gotodo.Service decorators which need the
+// context of the request they are called for, e.g. to log its ID.
+type contextBinder interface {
+	WithContext(ctx context.Context) gotodo.Service
+}
+
+// service returns the Service bound to the context of r, if it supports it.
+func (s *Server) service(r *http.Request) gotodo.Service {
+	if binder, ok := s.Service.(contextBinder); ok {
+		return binder.WithContext(r.Context())
+	}
+
+	return s.Service
+}
+
+// LogService returns a gotodo.Service which writes a debug log entry to logger
+// for every call made to svc. When used as Server.Service, the entries carry
+// the ID of the request the call is made for.
+func LogService(logger *slog.Logger, svc gotodo.Service) gotodo.Service {
+	return &loggedService{svc: svc, logger: logger, ctx: context.Background()}
+}
+
+type loggedService struct {
+	svc    gotodo.Service
+	logger *slog.Logger
+	ctx    context.Context
+}
+
+func (s *loggedService) WithContext(ctx context.Context) gotodo.Service {
+	return &loggedService{svc: s.svc, logger: s.logger, ctx: ctx}
+}
+
+func (s *loggedService) log(method string, start time.Time, err error) {
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if id := logging.RequestID(s.ctx); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		s.logger.LogAttrs(s.ctx, slog.LevelWarn, "service call", attrs...)
+		return
+	}
+
+	s.logger.LogAttrs(s.ctx, slog.LevelDebug, "service call", attrs...)
+}
+
+func (s *loggedService) Get(id int) (*gotodo.Todo, error) {
+	start := time.Now()
+	todo, err := s.svc.Get(id)
+	s.log("Get", start, err)
+
+	return todo, err
+}
+
+func (s *loggedService) GetAll() []*gotodo.Todo {
+	start := time.Now()
+	todos := s.svc.GetAll()
+	s.log("GetAll", start, nil)
+
+	return todos
+}
+
+func (s *loggedService) GetPending() []*gotodo.Todo {
+	start := time.Now()
+	todos := s.svc.GetPending()
+	s.log("GetPending", start, nil)
+
+	return todos
+}
+
+func (s *loggedService) GetFinished() []*gotodo.Todo {
+	start := time.Now()
+	todos := s.svc.GetFinished()
+	s.log("GetFinished", start, nil)
+
+	return todos
+}
+
+func (s *loggedService) Add(title string, description string) (*gotodo.Todo, error) {
+	start := time.Now()
+	todo, err := s.svc.Add(title, description)
+	s.log("Add", start, err)
+
+	return todo, err
+}
+
+func (s *loggedService) Edit(todo *gotodo.Todo) error {
+	start := time.Now()
+	err := s.svc.Edit(todo)
+	s.log("Edit", start, err)
+
+	return err
+}
+
+func (s *loggedService) MarkAsDone(todo *gotodo.Todo) error {
+	start := time.Now()
+	err := s.svc.MarkAsDone(todo)
+	s.log("MarkAsDone", start, err)
+
+	return err
+}
+
+func (s *loggedService) Delete(todo *gotodo.Todo) error {
+	start := time.Now()
+	err := s.svc.Delete(todo)
+	s.log("Delete", start, err)
+
+	return err
+}
+
+func (s *loggedService) DeleteFinished() {
+	start := time.Now()
+	s.svc.DeleteFinished()
+	s.log("DeleteFinished", start, nil)
+}
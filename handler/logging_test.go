@@ -0,0 +1,86 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/handler"
+	"github.com/saifulwebid/gotodoapp/logging"
+)
+
+func decodeLogEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	entries := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func TestLogRequests(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger, err := logging.New(buf, "debug", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := newExampleService()
+	h := handler.LogRequests(logger, handler.NewServer(handler.LogService(logger, svc)))
+
+	t.Run("generated request id", func(t *testing.T) {
+		buf.Reset()
+
+		rr := execute(h, httptest.NewRequest("GET", "/v1/1", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		id := rr.Header().Get(handler.RequestIDHeader)
+		assert.Len(t, id, 32)
+
+		entries := decodeLogEntries(t, buf)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, "service call", entries[0]["msg"])
+			assert.Equal(t, "Get", entries[0]["method"])
+			assert.Equal(t, id, entries[0]["request_id"])
+
+			assert.Equal(t, "request", entries[1]["msg"])
+			assert.Equal(t, id, entries[1]["request_id"])
+			assert.Equal(t, "GET", entries[1]["method"])
+			assert.Equal(t, "/v1/:id", entries[1]["route"])
+			assert.Equal(t, float64(http.StatusOK), entries[1]["status"])
+			assert.Equal(t, float64(rr.Body.Len()), entries[1]["bytes"])
+			assert.Contains(t, entries[1], "duration")
+			assert.Contains(t, entries[1], "remote_addr")
+		}
+	})
+
+	t.Run("accepted request id", func(t *testing.T) {
+		buf.Reset()
+
+		req := httptest.NewRequest("GET", "/v1/1", nil)
+		req.Header.Set(handler.RequestIDHeader, "abc-123")
+		rr := execute(h, req)
+
+		assert.Equal(t, "abc-123", rr.Header().Get(handler.RequestIDHeader))
+		for _, entry := range decodeLogEntries(t, buf) {
+			assert.Equal(t, "abc-123", entry["request_id"])
+		}
+	})
+
+	t.Run("invalid request id", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/1", nil)
+		req.Header.Set(handler.RequestIDHeader, "not valid")
+		rr := execute(h, req)
+
+		assert.NotEqual(t, "not valid", rr.Header().Get(handler.RequestIDHeader))
+	})
+}
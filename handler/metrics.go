@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+// Metrics collects Prometheus metrics about the HTTP traffic of a Server and
+// the calls made to its gotodo.Service.
+type Metrics struct {
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	inFlight        prometheus.Gauge
+
+	calls        *prometheus.HistogramVec
+	callFailures *prometheus.CounterVec
+
+	registerer prometheus.Registerer
+}
+
+// NewMetrics creates the metrics and registers them to registerer.
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "gotodo",
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "gotodo",
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "code"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "gotodo",
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of HTTP requests being served.",
+		}),
+		calls: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "gotodo",
+			Subsystem: "service",
+			Name:      "call_duration_seconds",
+			Help:      "Latency of gotodo.Service calls by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		callFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "gotodo",
+			Subsystem: "service",
+			Name:      "call_errors_total",
+			Help:      "Number of gotodo.Service calls returning an error by method.",
+		}, []string{"method"}),
+		registerer: registerer,
+	}
+
+	registerer.MustRegister(m.requests, m.requestDuration, m.inFlight, m.calls, m.callFailures)
+
+	return m
+}
+
+// Wrap returns a http.Handler which records metrics about the requests served
+// by next.
+func (m *Metrics) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		r, route := trackRoute(r)
+		rec := newResponseRecorder(w)
+		start := time.Now()
+
+		next.ServeHTTP(rec, r)
+
+		labels := prometheus.Labels{
+			"method": r.Method,
+			"route":  route.Route(),
+			"code":   strconv.Itoa(rec.Status()),
+		}
+		m.requests.With(labels).Inc()
+		m.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// WatchTodos registers gauges reporting the number of pending and finished
+// Todos in svc. svc is queried on every scrape.
+func (m *Metrics) WatchTodos(svc gotodo.Service) {
+	pending := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   "gotodo",
+		Name:        "todos",
+		Help:        "Number of Todos by state.",
+		ConstLabels: prometheus.Labels{"state": "pending"},
+	}, func() float64 {
+		return float64(len(svc.GetPending()))
+	})
+	finished := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   "gotodo",
+		Name:        "todos",
+		Help:        "Number of Todos by state.",
+		ConstLabels: prometheus.Labels{"state": "finished"},
+	}, func() float64 {
+		return float64(len(svc.GetFinished()))
+	})
+
+	m.registerer.MustRegister(pending, finished)
+}
+
+// InstrumentService returns a gotodo.Service which records the latency and
+// errors of calls made to svc.
+func (m *Metrics) InstrumentService(svc gotodo.Service) gotodo.Service {
+	return &instrumentedService{svc: svc, metrics: m}
+}
+
+type instrumentedService struct {
+	svc     gotodo.Service
+	metrics *Metrics
+}
+
+func (s *instrumentedService) observe(method string, start time.Time, err error) {
+	s.metrics.calls.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		s.metrics.callFailures.WithLabelValues(method).Inc()
+	}
+}
+
+func (s *instrumentedService) Get(id int) (*gotodo.Todo, error) {
+	start := time.Now()
+	todo, err := s.svc.Get(id)
+	s.observe("Get", start, err)
+
+	return todo, err
+}
+
+func (s *instrumentedService) GetAll() []*gotodo.Todo {
+	start := time.Now()
+	todos := s.svc.GetAll()
+	s.observe("GetAll", start, nil)
+
+	return todos
+}
+
+func (s *instrumentedService) GetPending() []*gotodo.Todo {
+	start := time.Now()
+	todos := s.svc.GetPending()
+	s.observe("GetPending", start, nil)
+
+	return todos
+}
+
+func (s *instrumentedService) GetFinished() []*gotodo.Todo {
+	start := time.Now()
+	todos := s.svc.GetFinished()
+	s.observe("GetFinished", start, nil)
+
+	return todos
+}
+
+func (s *instrumentedService) Add(title string, description string) (*gotodo.Todo, error) {
+	start := time.Now()
+	todo, err := s.svc.Add(title, description)
+	s.observe("Add", start, err)
+
+	return todo, err
+}
+
+func (s *instrumentedService) Edit(todo *gotodo.Todo) error {
+	start := time.Now()
+	err := s.svc.Edit(todo)
+	s.observe("Edit", start, err)
+
+	return err
+}
+
+func (s *instrumentedService) MarkAsDone(todo *gotodo.Todo) error {
+	start := time.Now()
+	err := s.svc.MarkAsDone(todo)
+	s.observe("MarkAsDone", start, err)
+
+	return err
+}
+
+func (s *instrumentedService) Delete(todo *gotodo.Todo) error {
+	start := time.Now()
+	err := s.svc.Delete(todo)
+	s.observe("Delete", start, err)
+
+	return err
+}
+
+func (s *instrumentedService) DeleteFinished() {
+	start := time.Now()
+	s.svc.DeleteFinished()
+	s.observe("DeleteFinished", start, nil)
+}
@@ -0,0 +1,52 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/handler"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := handler.NewMetrics(registry)
+
+	svc := newExampleService()
+	svc.GetFn = func(id int) (*gotodo.Todo, error) {
+		if id != 1 {
+			return nil, errors.New("not found")
+		}
+
+		return &gotodo.Todo{ID: 1, Title: "Buy milk"}, nil
+	}
+	metrics.WatchTodos(svc)
+
+	server := handler.NewServer(metrics.InstrumentService(svc))
+	server.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	h := metrics.Wrap(server)
+
+	execute(h, httptest.NewRequest("GET", "/v1/1", nil))
+	execute(h, httptest.NewRequest("GET", "/v1/2", nil))
+	execute(h, httptest.NewRequest("GET", "/v1/a/b/c", nil))
+
+	rr := execute(h, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.String()
+	assert.Contains(t, body, `gotodo_http_requests_total{code="200",method="GET",route="/v1/:id"} 1`)
+	assert.Contains(t, body, `gotodo_http_requests_total{code="404",method="GET",route="/v1/:id"} 1`)
+	assert.Contains(t, body, `gotodo_http_requests_total{code="404",method="GET",route="unmatched"} 1`)
+	assert.Contains(t, body, `gotodo_http_request_duration_seconds_count{code="200",method="GET",route="/v1/:id"} 1`)
+	assert.Contains(t, body, `gotodo_http_requests_in_flight 1`)
+	assert.Contains(t, body, `gotodo_service_call_duration_seconds_count{method="Get"} 2`)
+	assert.Contains(t, body, `gotodo_service_call_errors_total{method="Get"} 1`)
+	assert.Contains(t, body, `gotodo_todos{state="pending"} 1`)
+	assert.Contains(t, body, `gotodo_todos{state="finished"} 0`)
+}
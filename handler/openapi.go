@@ -0,0 +1,471 @@
+package handler
+
+import (
+	"net/http"
+)
+
+// openAPISpec is the OpenAPI 3 document describing every route registered in
+// NewServer. Examples in this document are replayed against Server by the
+// handler tests, so keep them valid when changing a route.
+const openAPISpec = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "gotodoserver",
+    "description": "A web server that wraps calls to the gotodo package. The routes are also served without the /v1 prefix as a deprecated alias, and the version can be negotiated through an Accept header of application/vnd.gotodo.v1+json.",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/v1/": {
+      "get": {
+        "operationId": "getTodos",
+        "summary": "Get all Todos, optionally filtered by their done state and timestamps.",
+        "parameters": [
+          {
+            "name": "done",
+            "in": "query",
+            "required": false,
+            "schema": {"type": "string", "enum": ["true", "false"]},
+            "example": "false"
+          },
+          {
+            "name": "since",
+            "in": "query",
+            "required": false,
+            "description": "Only the Todos created, updated or completed from this time: an RFC 3339 time, a date, or a duration ago such as 36h or 7d. It requires the server to record timestamps.",
+            "schema": {"type": "string"}
+          },
+          {
+            "name": "until",
+            "in": "query",
+            "required": false,
+            "description": "Only the Todos created, updated or completed before this time, or on this date. It takes the same forms as since.",
+            "schema": {"type": "string"}
+          },
+          {
+            "name": "timestamp",
+            "in": "query",
+            "required": false,
+            "description": "The timestamp since and until apply to.",
+            "schema": {"type": "string", "enum": ["created", "updated", "completed"]},
+            "example": "created"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "List of Todos.",
+            "content": {
+              "application/json": {
+                "schema": {"type": "array", "items": {"$ref": "#/components/schemas/Todo"}},
+                "example": [{"id": 1, "title": "Buy milk", "description": "Two bottles", "done": false}]
+              }
+            }
+          },
+          "304": {"$ref": "#/components/responses/NotModified"},
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      },
+      "post": {
+        "operationId": "addTodo",
+        "summary": "Create a Todo.",
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {"$ref": "#/components/schemas/TodoInput"},
+              "example": {"title": "Buy milk", "description": "Two bottles"}
+            }
+          }
+        },
+        "responses": {
+          "201": {
+            "description": "The created Todo.",
+            "content": {
+              "application/json": {
+                "schema": {"$ref": "#/components/schemas/Todo"},
+                "example": {"id": 1, "title": "Buy milk", "description": "Two bottles", "done": false}
+              }
+            }
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "500": {"$ref": "#/components/responses/InternalServerError"}
+        }
+      },
+      "delete": {
+        "operationId": "deleteFinishedTodos",
+        "summary": "Delete all finished Todos.",
+        "parameters": [
+          {
+            "name": "done",
+            "in": "query",
+            "required": true,
+            "schema": {"type": "string", "enum": ["true"]},
+            "example": "true"
+          }
+        ],
+        "responses": {
+          "200": {"description": "Finished Todos are deleted."},
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      }
+    },
+    "/v1/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "get": {
+        "operationId": "getTodo",
+        "summary": "Get a Todo.",
+        "responses": {
+          "200": {
+            "description": "The requested Todo.",
+            "content": {
+              "application/json": {
+                "schema": {"$ref": "#/components/schemas/Todo"},
+                "example": {"id": 1, "title": "Buy milk", "description": "Two bottles", "done": false}
+              }
+            }
+          },
+          "304": {"$ref": "#/components/responses/NotModified"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      },
+      "patch": {
+        "operationId": "editTodo",
+        "summary": "Modify the title and description of a Todo.",
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {"$ref": "#/components/schemas/TodoInput"},
+              "example": {"title": "Buy oat milk"}
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "The modified Todo.",
+            "content": {
+              "application/json": {
+                "schema": {"$ref": "#/components/schemas/Todo"},
+                "example": {"id": 1, "title": "Buy oat milk", "description": "Two bottles", "done": false}
+              }
+            }
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "500": {"$ref": "#/components/responses/InternalServerError"}
+        }
+      },
+      "delete": {
+        "operationId": "deleteTodo",
+        "summary": "Delete a Todo.",
+        "responses": {
+          "200": {"description": "The Todo is deleted."},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "500": {"$ref": "#/components/responses/InternalServerError"}
+        }
+      }
+    },
+    "/v1/{id}/done": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "put": {
+        "operationId": "markTodoAsDone",
+        "summary": "Mark a Todo as done.",
+        "responses": {
+          "200": {
+            "description": "The finished Todo.",
+            "content": {
+              "application/json": {
+                "schema": {"$ref": "#/components/schemas/Todo"},
+                "example": {"id": 1, "title": "Buy milk", "description": "Two bottles", "done": true}
+              }
+            }
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "500": {"$ref": "#/components/responses/InternalServerError"}
+        }
+      }
+    },
+    "/graphql": {
+      "get": {
+        "operationId": "getGraphQL",
+        "summary": "Run a GraphQL query on the Todos. Mutations must be sent with POST.",
+        "parameters": [
+          {"name": "query", "in": "query", "required": true, "schema": {"type": "string"}, "example": "{todos{id,title}}"},
+          {"name": "operationName", "in": "query", "required": false, "schema": {"type": "string"}},
+          {"name": "variables", "in": "query", "required": false, "description": "The variables of the query, as a JSON object.", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {"$ref": "#/components/responses/GraphQLResult"},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "405": {"description": "The query is a mutation.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+        }
+      },
+      "post": {
+        "operationId": "postGraphQL",
+        "summary": "Run a GraphQL query or mutation on the Todos, or a batch of them sent as an array.",
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "oneOf": [
+                  {"$ref": "#/components/schemas/GraphQLRequest"},
+                  {"type": "array", "items": {"$ref": "#/components/schemas/GraphQLRequest"}}
+                ]
+              },
+              "example": {"query": "mutation ($title: String!) { addTodo(title: $title) { id title } }", "variables": {"title": "Buy milk"}}
+            }
+          }
+        },
+        "responses": {
+          "200": {"$ref": "#/components/responses/GraphQLResult"},
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      }
+    },
+    "/healthz": {
+      "get": {
+        "operationId": "getHealth",
+        "summary": "Report that the server process is alive.",
+        "responses": {
+          "200": {
+            "description": "The server is alive.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Status"}, "example": {"status": "ok"}}}
+          }
+        }
+      }
+    },
+    "/readyz": {
+      "get": {
+        "operationId": "getReadiness",
+        "summary": "Report whether the storage is reachable.",
+        "responses": {
+          "200": {
+            "description": "The storage is reachable.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Status"}, "example": {"status": "ok"}}}
+          },
+          "503": {
+            "description": "The storage is not reachable.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Status"}, "example": {"status": "unavailable", "error": "storage probe timed out"}}}
+          }
+        }
+      }
+    },
+    "/stats": {
+      "get": {
+        "operationId": "getStats",
+        "summary": "Report how many Todos are pending and finished, and how many were created and completed over time.",
+        "parameters": [
+          {"name": "by", "in": "query", "schema": {"type": "string", "enum": ["day", "week"]}, "example": "week"},
+          {"name": "last", "in": "query", "schema": {"type": "integer"}, "example": 8},
+          {"name": "oldest", "in": "query", "schema": {"type": "integer"}, "example": 5}
+        ],
+        "responses": {
+          "200": {
+            "description": "The statistics. The history is empty if the server does not record when Todos are created and completed.",
+            "content": {
+              "application/json": {
+                "schema": {"$ref": "#/components/schemas/Stats"},
+                "example": {
+                  "pending": 2,
+                  "finished": 6,
+                  "completion_rate": 0.75,
+                  "by": "week",
+                  "history": [{"start": "2018-10-01T00:00:00Z", "created": 5, "completed": 4}],
+                  "oldest_pending": [{"id": 1, "title": "Buy milk", "created_at": "2018-09-24T09:30:00Z"}]
+                }
+              }
+            }
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      }
+    },
+    "/version": {
+      "get": {
+        "operationId": "getVersion",
+        "summary": "Get the build information of the server.",
+        "responses": {
+          "200": {
+            "description": "The build information.",
+            "content": {
+              "application/json": {
+                "schema": {"$ref": "#/components/schemas/Version"},
+                "example": {"commit": "3c92a29c40581a20970993f738676514904328dd", "build_time": "2018-10-01T00:00:00Z", "go_version": "go1.11"}
+              }
+            }
+          }
+        }
+      }
+    },
+    "/openapi.json": {
+      "get": {
+        "operationId": "getOpenAPI",
+        "summary": "Get this OpenAPI document.",
+        "responses": {
+          "200": {
+            "description": "The OpenAPI document.",
+            "content": {"application/json": {"schema": {"type": "object"}}}
+          }
+        }
+      }
+    }
+  },
+  "security": [{"bearer": []}, {}],
+  "components": {
+    "securitySchemes": {
+      "bearer": {"type": "http", "scheme": "bearer", "description": "Required by every route but /healthz and /readyz if the server is configured with a token."}
+    },
+    "parameters": {
+      "ID": {
+        "name": "id",
+        "in": "path",
+        "required": true,
+        "schema": {"type": "integer"},
+        "example": 1
+      }
+    },
+    "schemas": {
+      "Todo": {
+        "type": "object",
+        "required": ["id", "title", "description", "done"],
+        "properties": {
+          "id": {"type": "integer"},
+          "title": {"type": "string"},
+          "description": {"type": "string"},
+          "done": {"type": "boolean"},
+          "created_at": {"type": "string", "format": "date-time", "description": "Left out if unknown, as are updated_at and completed_at."},
+          "updated_at": {"type": "string", "format": "date-time"},
+          "completed_at": {"type": "string", "format": "date-time"}
+        }
+      },
+      "TodoInput": {
+        "type": "object",
+        "properties": {
+          "title": {"type": "string"},
+          "description": {"type": "string"}
+        }
+      },
+      "Status": {
+        "type": "object",
+        "required": ["status"],
+        "properties": {
+          "status": {"type": "string"},
+          "error": {"type": "string"}
+        }
+      },
+      "Version": {
+        "type": "object",
+        "required": ["commit", "build_time", "go_version"],
+        "properties": {
+          "commit": {"type": "string"},
+          "build_time": {"type": "string"},
+          "go_version": {"type": "string"}
+        }
+      },
+      "Stats": {
+        "type": "object",
+        "required": ["pending", "finished", "completion_rate", "by", "history", "oldest_pending"],
+        "properties": {
+          "pending": {"type": "integer"},
+          "finished": {"type": "integer"},
+          "completion_rate": {"type": "number"},
+          "by": {"type": "string", "enum": ["day", "week"]},
+          "history": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "required": ["start", "created", "completed"],
+              "properties": {
+                "start": {"type": "string", "format": "date-time"},
+                "created": {"type": "integer"},
+                "completed": {"type": "integer"}
+              }
+            }
+          },
+          "oldest_pending": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "required": ["id", "title"],
+              "properties": {
+                "id": {"type": "integer"},
+                "title": {"type": "string"},
+                "created_at": {"type": "string", "format": "date-time"}
+              }
+            }
+          }
+        }
+      },
+      "Error": {
+        "type": "object",
+        "required": ["error"],
+        "properties": {
+          "error": {"type": "string"}
+        }
+      },
+      "GraphQLRequest": {
+        "type": "object",
+        "required": ["query"],
+        "properties": {
+          "query": {"type": "string"},
+          "operationName": {"type": "string"},
+          "variables": {"type": "object"}
+        }
+      },
+      "GraphQLResult": {
+        "type": "object",
+        "properties": {
+          "data": {"type": "object"},
+          "errors": {"type": "array", "items": {"type": "object"}}
+        }
+      }
+    },
+    "responses": {
+      "NotModified": {
+        "description": "No Todo is modified since the time given in the If-Modified-Since header."
+      },
+      "BadRequest": {
+        "description": "The request cannot be parsed.",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}
+      },
+      "NotFound": {
+        "description": "The Todo does not exist.",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}
+      },
+      "InternalServerError": {
+        "description": "The service failed to process the request.",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}
+      },
+      "GraphQLResult": {
+        "description": "The result of the GraphQL request, or an array of them for a batch. Errors of the query are reported in it, not by the status code.",
+        "content": {
+          "application/json": {
+            "schema": {
+              "oneOf": [
+                {"$ref": "#/components/schemas/GraphQLResult"},
+                {"type": "array", "items": {"$ref": "#/components/schemas/GraphQLResult"}}
+              ]
+            },
+            "example": {"data": {"todos": [{"id": 1, "title": "Buy milk"}]}}
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// OpenAPI is a handler for GET "/openapi.json" route. It returns the OpenAPI 3
+// document describing this server.
+func (s *Server) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	if !allowGetOnly(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(openAPISpec))
+}
@@ -0,0 +1,266 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/handler"
+)
+
+type specParameter struct {
+	Ref     string      `json:"$ref"`
+	Name    string      `json:"name"`
+	In      string      `json:"in"`
+	Example interface{} `json:"example"`
+}
+
+type specMedia struct {
+	Example interface{} `json:"example"`
+}
+
+type specOperation struct {
+	Parameters  []specParameter `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]specMedia `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]specMedia `json:"content"`
+	} `json:"responses"`
+}
+
+type spec struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Parameters map[string]specParameter `json:"parameters"`
+	} `json:"components"`
+}
+
+func newExampleService() *mockService {
+	todo := func() *gotodo.Todo {
+		return &gotodo.Todo{ID: 1, Title: "Buy milk", Description: "Two bottles", Done: false}
+	}
+
+	return &mockService{
+		GetFn: func(id int) (*gotodo.Todo, error) {
+			return todo(), nil
+		},
+		GetAllFn: func() []*gotodo.Todo {
+			return []*gotodo.Todo{todo()}
+		},
+		GetPendingFn: func() []*gotodo.Todo {
+			return []*gotodo.Todo{todo()}
+		},
+		GetFinishedFn: func() []*gotodo.Todo {
+			return []*gotodo.Todo{}
+		},
+		AddFn: func(title string, description string) (*gotodo.Todo, error) {
+			return &gotodo.Todo{ID: 1, Title: title, Description: description}, nil
+		},
+		EditFn: func(todo *gotodo.Todo) error {
+			return nil
+		},
+		MarkAsDoneFn: func(todo *gotodo.Todo) error {
+			todo.Done = true
+			return nil
+		},
+		DeleteFn: func(todo *gotodo.Todo) error {
+			return nil
+		},
+		DeleteFinishedFn: func() {},
+	}
+}
+
+func fetchSpec(t *testing.T, h http.Handler) *spec {
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/openapi.json", nil))
+
+	if !assert.Equal(t, http.StatusOK, rr.Code) {
+		t.FailNow()
+	}
+
+	doc := &spec{}
+	if err := json.Unmarshal(rr.Body.Bytes(), doc); err != nil {
+		t.Fatal(err)
+	}
+
+	return doc
+}
+
+// jsonKeys returns the sorted keys of a JSON object, or of the first element of
+// a JSON array of objects.
+func jsonKeys(v interface{}) []string {
+	if arr, ok := v.([]interface{}); ok {
+		if len(arr) == 0 {
+			return nil
+		}
+		v = arr[0]
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	keys := []string{}
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	h := handler.NewServer(newExampleService())
+	doc := fetchSpec(t, h)
+
+	for _, route := range h.Routes() {
+		method, path := "", route
+		if i := strings.Index(route, " "); i >= 0 {
+			method, path = strings.ToLower(route[:i]), route[i+1:]
+		}
+		path = strings.Replace(path, ":id", "{id}", 1)
+
+		item, ok := doc.Paths[path]
+		if !assert.True(t, ok, "%s is not documented", route) || method == "" {
+			continue
+		}
+		assert.Contains(t, item, method, "%s is not documented", route)
+	}
+}
+
+func TestOpenAPIExamples(t *testing.T) {
+	h := handler.NewServer(newExampleService())
+	doc := fetchSpec(t, h)
+
+	assert.True(t, strings.HasPrefix(doc.OpenAPI, "3."))
+
+	for path, item := range doc.Paths {
+		var shared []specParameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for method, raw := range item {
+			if method == "parameters" {
+				continue
+			}
+
+			op := &specOperation{}
+			if err := json.Unmarshal(raw, op); err != nil {
+				t.Fatal(err)
+			}
+
+			t.Run(strings.ToUpper(method)+" "+path, func(t *testing.T) {
+				target := path
+				query := []string{}
+				for _, p := range append(shared, op.Parameters...) {
+					if p.Ref != "" {
+						p = doc.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+					}
+					if p.Example == nil {
+						continue
+					}
+
+					example, _ := json.Marshal(p.Example)
+					value := strings.Trim(string(example), `"`)
+					switch p.In {
+					case "path":
+						target = strings.Replace(target, "{"+p.Name+"}", value, 1)
+					case "query":
+						query = append(query, p.Name+"="+value)
+					}
+				}
+				if len(query) > 0 {
+					target += "?" + strings.Join(query, "&")
+				}
+
+				var body io.Reader
+				if op.RequestBody != nil {
+					payload, _ := json.Marshal(op.RequestBody.Content["application/json"].Example)
+					body = bytes.NewBuffer(payload)
+				}
+
+				rr := execute(h, httptest.NewRequest(strings.ToUpper(method), target, body))
+
+				codes := []string{}
+				for code := range op.Responses {
+					codes = append(codes, code)
+				}
+				sort.Strings(codes)
+
+				// The first documented status code is the successful one.
+				if !assert.Equal(t, codes[0], strconv.Itoa(rr.Code)) {
+					return
+				}
+
+				example := op.Responses[codes[0]].Content["application/json"].Example
+				if example == nil {
+					return
+				}
+
+				var actual interface{}
+				if err := json.Unmarshal(rr.Body.Bytes(), &actual); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, jsonKeys(example), jsonKeys(actual))
+			})
+		}
+	}
+}
+
+func TestRequestValidator(t *testing.T) {
+	svc := newExampleService()
+	h := handler.NewRequestValidator(handler.NewServer(svc))
+
+	t.Run("valid payload", func(t *testing.T) {
+		svc.AddInvoked = 0
+		payload := []byte(`{"title": "Title", "description": "desc"}`)
+
+		rr := execute(h, httptest.NewRequest("POST", "/v1/", bytes.NewBuffer(payload)))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, 1, svc.AddInvoked)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		svc.AddInvoked = 0
+		payload := []byte(`{"title": 1}`)
+
+		rr := execute(h, httptest.NewRequest("POST", "/v1/", bytes.NewBuffer(payload)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, 0, svc.AddInvoked)
+	})
+
+	t.Run("invalid query string", func(t *testing.T) {
+		svc.GetAllInvoked = 0
+
+		rr := execute(h, httptest.NewRequest("GET", "/v1/?done=maybe", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, 0, svc.GetAllInvoked)
+	})
+
+	t.Run("missing required query string", func(t *testing.T) {
+		svc.DeleteFinishedInvoked = 0
+
+		rr := execute(h, httptest.NewRequest("DELETE", "/", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, 0, svc.DeleteFinishedInvoked)
+	})
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitOptions configures a RateLimiter. Rates are in requests per second;
+// bursts are the number of requests a client may make at once.
+type RateLimitOptions struct {
+	ReadRate   float64
+	ReadBurst  int
+	WriteRate  float64
+	WriteBurst int
+
+	// TrustedProxies are the IP addresses or CIDR ranges of proxies whose
+	// X-Forwarded-For header is trusted to carry the client IP address.
+	TrustedProxies []string
+
+	// EvictAfter is how long the state of an idle client is kept.
+	EvictAfter time.Duration
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills b for the time elapsed since it was last used, then takes a
+// token from it if there is one. It returns whether a token is taken, and the
+// time until the next token is available.
+func (b *bucket) take(now time.Time, rate float64, burst int) (bool, time.Duration) {
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+// RateLimiter is a middleware which limits the rate of requests of every
+// client with token buckets. Reads (GET, HEAD and OPTIONS requests) and writes
+// are limited separately. A client is identified by its user identity if it is
+// authenticated, or by its IP address otherwise.
+type RateLimiter struct {
+	opts    RateLimitOptions
+	proxies []*net.IPNet
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewRateLimiter returns a RateLimiter configured by opts.
+func NewRateLimiter(opts RateLimitOptions) (*RateLimiter, error) {
+	if opts.ReadRate <= 0 || opts.WriteRate <= 0 || opts.ReadBurst < 1 || opts.WriteBurst < 1 {
+		return nil, errors.New("rate limit rates and bursts must be positive")
+	}
+
+	l := &RateLimiter{opts: opts, buckets: map[string]*bucket{}}
+
+	for _, proxy := range opts.TrustedProxies {
+		if !strings.Contains(proxy, "/") {
+			if strings.Contains(proxy, ":") {
+				proxy += "/128"
+			} else {
+				proxy += "/32"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, err
+		}
+		l.proxies = append(l.proxies, network)
+	}
+
+	return l, nil
+}
+
+func (l *RateLimiter) trusted(ip net.IP) bool {
+	for _, network := range l.proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clientIP returns the IP address of the client making r. If r comes from a
+// trusted proxy, X-Forwarded-For is walked from the nearest hop until an
+// untrusted address is found.
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !l.trusted(ip) {
+		return host
+	}
+
+	hops := strings.Split(strings.Join(r.Header["X-Forwarded-For"], ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			break
+		}
+
+		ip = hop
+		if !l.trusted(hop) {
+			break
+		}
+	}
+
+	return ip.String()
+}
+
+func isRead(method string) bool {
+	return method == "GET" || method == "HEAD" || method == "OPTIONS"
+}
+
+// Wrap returns a http.Handler which serves requests with next as long as their
+// client is within its limits, and answers 429 Too Many Requests otherwise.
+func (l *RateLimiter) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := "ip:" + l.clientIP(r)
+		if user := User(r.Context()); user != "" {
+			client = "user:" + user
+		}
+
+		kind, rate, burst := "write", l.opts.WriteRate, l.opts.WriteBurst
+		if isRead(r.Method) {
+			kind, rate, burst = "read", l.opts.ReadRate, l.opts.ReadBurst
+		}
+
+		now := time.Now()
+		key := kind + " " + client
+
+		l.mu.Lock()
+		b, ok := l.buckets[key]
+		if !ok {
+			b = &bucket{tokens: float64(burst), last: now}
+			l.buckets[key] = b
+		}
+		allowed, wait := b.take(now, rate, burst)
+		remaining := int(b.tokens)
+		reset := (float64(burst) - b.tokens) / rate
+		l.mu.Unlock()
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(burst))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(int(math.Ceil(reset))))
+
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			respondWithErrorInJSON(w, http.StatusTooManyRequests, errors.New("rate limit exceeded"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Evict forgets the clients which are idle for longer than EvictAfter. It
+// returns the number of buckets removed.
+func (l *RateLimiter) Evict() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	evicted := 0
+	cutoff := time.Now().Add(-l.opts.EvictAfter)
+	for key, b := range l.buckets {
+		if b.last.Before(cutoff) {
+			delete(l.buckets, key)
+			evicted++
+		}
+	}
+
+	return evicted
+}
+
+// StartEviction calls Evict every interval until ctx is done.
+func (l *RateLimiter) StartEviction(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.Evict()
+			}
+		}
+	}()
+}
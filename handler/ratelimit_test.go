@@ -0,0 +1,119 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/handler"
+)
+
+func newRateLimiter(t *testing.T) *handler.RateLimiter {
+	l, err := handler.NewRateLimiter(handler.RateLimitOptions{
+		ReadRate:       0.01,
+		ReadBurst:      3,
+		WriteRate:      0.01,
+		WriteBurst:     1,
+		TrustedProxies: []string{"10.0.0.0/8"},
+		EvictAfter:     time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return l
+}
+
+func requestFrom(method string, remoteAddr string, forwardedFor string) *http.Request {
+	req := httptest.NewRequest(method, "/v1/", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+
+	return req
+}
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("reads and writes are limited separately", func(t *testing.T) {
+		h := newRateLimiter(t).Wrap(handler.NewServer(newExampleService()))
+
+		for i := 0; i < 3; i++ {
+			rr := execute(h, requestFrom("GET", "192.0.2.1:1234", ""))
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, "3", rr.Header().Get("RateLimit-Limit"))
+		}
+
+		rr := execute(h, requestFrom("GET", "192.0.2.1:1234", ""))
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+		assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+		assert.NotEmpty(t, rr.Header().Get("RateLimit-Reset"))
+
+		rr = execute(h, requestFrom("DELETE", "192.0.2.1:1234", ""))
+		assert.NotEqual(t, http.StatusTooManyRequests, rr.Code)
+
+		rr = execute(h, requestFrom("DELETE", "192.0.2.1:1234", ""))
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	})
+
+	t.Run("clients are limited separately", func(t *testing.T) {
+		h := newRateLimiter(t).Wrap(handler.NewServer(newExampleService()))
+
+		rr := execute(h, requestFrom("DELETE", "192.0.2.1:1234", ""))
+		assert.NotEqual(t, http.StatusTooManyRequests, rr.Code)
+
+		rr = execute(h, requestFrom("DELETE", "192.0.2.2:1234", ""))
+		assert.NotEqual(t, http.StatusTooManyRequests, rr.Code)
+	})
+
+	t.Run("forwarded for by trusted proxy", func(t *testing.T) {
+		h := newRateLimiter(t).Wrap(handler.NewServer(newExampleService()))
+
+		rr := execute(h, requestFrom("DELETE", "10.0.0.1:1234", "192.0.2.1, 10.0.0.2"))
+		assert.NotEqual(t, http.StatusTooManyRequests, rr.Code)
+
+		rr = execute(h, requestFrom("DELETE", "10.0.0.3:1234", "192.0.2.1"))
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+		rr = execute(h, requestFrom("DELETE", "10.0.0.3:1234", "192.0.2.2"))
+		assert.NotEqual(t, http.StatusTooManyRequests, rr.Code)
+	})
+
+	t.Run("forwarded for by untrusted proxy", func(t *testing.T) {
+		h := newRateLimiter(t).Wrap(handler.NewServer(newExampleService()))
+
+		rr := execute(h, requestFrom("DELETE", "192.0.2.9:1234", "192.0.2.1"))
+		assert.NotEqual(t, http.StatusTooManyRequests, rr.Code)
+
+		rr = execute(h, requestFrom("DELETE", "192.0.2.9:1234", "192.0.2.2"))
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	})
+
+	t.Run("authenticated users", func(t *testing.T) {
+		h := newRateLimiter(t).Wrap(handler.NewServer(newExampleService()))
+
+		for _, user := range []string{"alice", "bob"} {
+			req := requestFrom("DELETE", "192.0.2.1:1234", "")
+			req = req.WithContext(handler.WithUser(req.Context(), user))
+
+			rr := execute(h, req)
+			assert.NotEqual(t, http.StatusTooManyRequests, rr.Code)
+		}
+	})
+
+	t.Run("eviction", func(t *testing.T) {
+		l := newRateLimiter(t)
+		h := l.Wrap(handler.NewServer(newExampleService()))
+
+		execute(h, requestFrom("GET", "192.0.2.1:1234", ""))
+		execute(h, requestFrom("GET", "192.0.2.2:1234", ""))
+		time.Sleep(5 * time.Millisecond)
+
+		assert.Equal(t, 2, l.Evict())
+		assert.Equal(t, 0, l.Evict())
+	})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type routeKey struct{}
+
+// routeHolder is put in the request context by middlewares which want to know
+// the route pattern matching the request, e.g. "/v1/:id" instead of "/v1/42".
+// Server fills it in when it dispatches the request.
+type routeHolder struct {
+	route string
+}
+
+// trackRoute returns a copy of r carrying a routeHolder. If r already carries
+// one, r and that holder are returned as is.
+func trackRoute(r *http.Request) (*http.Request, *routeHolder) {
+	if holder, ok := r.Context().Value(routeKey{}).(*routeHolder); ok {
+		return r, holder
+	}
+
+	holder := &routeHolder{}
+	return r.WithContext(context.WithValue(r.Context(), routeKey{}, holder)), holder
+}
+
+// Route returns the route pattern of the holder, or "unmatched" if no route
+// matched the request.
+func (h *routeHolder) Route() string {
+	if h.route == "" {
+		return "unmatched"
+	}
+
+	return h.route
+}
+
+func setRoute(r *http.Request, route string) {
+	if holder, ok := r.Context().Value(routeKey{}).(*routeHolder); ok {
+		holder.route = route
+	}
+}
+
+func withRoute(route string, handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		setRoute(r, route)
+		handle(w, r, ps)
+	}
+}
+
+func withRouteFunc(route string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		setRoute(r, route)
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/saifulwebid/gotodoapp/stats"
+	"github.com/saifulwebid/gotodoapp/timestamps"
+)
+
+// statsOptions parses the "by", "last" and "oldest" query strings of a request
+// to GET "/stats".
+func statsOptions(query url.Values) (stats.Options, error) {
+	opts := stats.Options{By: query.Get("by")}
+
+	for name, value := range map[string]*int{"last": &opts.Last, "oldest": &opts.Oldest} {
+		if query.Get(name) == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(query.Get(name))
+		if err != nil {
+			return opts, errors.New("cannot parse " + name)
+		}
+		*value = n
+	}
+
+	return opts, nil
+}
+
+// Stats is a handler for GET "/stats" route. It reports how many Todos are
+// pending and finished, how many were created and completed in each of the
+// last days or weeks, and the oldest pending Todos.
+//
+// The "by" query string is either "day" or "week", "last" is the number of
+// days or weeks reported, and "oldest" the number of oldest pending Todos.
+// The days and weeks are only reported if Server.Times is set.
+func (s *Server) Stats(w http.ResponseWriter, r *http.Request) {
+	if !allowGetOnly(w, r) {
+		return
+	}
+
+	opts, err := statsOptions(r.URL.Query())
+	if err != nil {
+		respondWithErrorInJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var times map[int]timestamps.Times
+	if s.Times != nil {
+		times, err = s.Times.Times()
+		if err != nil {
+			respondWithErrorInJSON(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	report, err := stats.Compute(s.service(r), times, opts)
+	if err != nil {
+		respondWithErrorInJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondInJSON(w, http.StatusOK, report)
+}
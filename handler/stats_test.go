@@ -0,0 +1,55 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/handler"
+	"github.com/saifulwebid/gotodoapp/stats"
+	"github.com/saifulwebid/gotodoapp/timestamps"
+)
+
+func TestStats(t *testing.T) {
+	svc := timestamps.NewService(&memService{}, timestamps.NewMemoryStore())
+	milk, _ := svc.Add("Buy milk", "")
+	svc.Add("Buy eggs", "")
+	svc.MarkAsDone(milk)
+
+	h := handler.NewServer(svc)
+	h.Times = svc
+
+	rr := execute(h, httptest.NewRequest("GET", "/stats?by=week&last=2&oldest=1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var report stats.Report
+	if assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report)) {
+		assert.Equal(t, 1, report.Pending)
+		assert.Equal(t, 1, report.Finished)
+		assert.Equal(t, 0.5, report.CompletionRate)
+		assert.Equal(t, stats.Week, report.By)
+		if assert.Len(t, report.History, 2) {
+			assert.Equal(t, 2, report.History[1].Created)
+			assert.Equal(t, 1, report.History[1].Completed)
+		}
+		if assert.Len(t, report.Oldest, 1) {
+			assert.Equal(t, "Buy eggs", report.Oldest[0].Title)
+			assert.NotNil(t, report.Oldest[0].CreatedAt)
+		}
+	}
+
+	rr = execute(h, httptest.NewRequest("GET", "/stats?by=month", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = execute(h, httptest.NewRequest("GET", "/stats?last=many", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	rr = execute(h, httptest.NewRequest("POST", "/stats", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+
+	// Without Times, there is no history.
+	rr = execute(handler.NewServer(svc), httptest.NewRequest("GET", "/stats", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"history":[]`)
+}
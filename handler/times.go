@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/timestamps"
+)
+
+// withTimes returns todo along with its Times, if Server.Times is set. The
+// Times are left out if they can not be read, as todo is not to be failed
+// for them.
+func (s *Server) withTimes(todo *gotodo.Todo) interface{} {
+	if s.Times == nil {
+		return todo
+	}
+
+	times, err := s.Times.Times(todo.ID)
+	if err != nil {
+		return todo
+	}
+
+	return timestamps.WithTimes(todo, times[todo.ID])
+}
+
+// listWithTimes returns todos along with their times.
+func listWithTimes(todos []*gotodo.Todo, times map[int]timestamps.Times) []*timestamps.Todo {
+	timed := make([]*timestamps.Todo, len(todos))
+	for i, todo := range todos {
+		timed[i] = timestamps.WithTimes(todo, times[todo.ID])
+	}
+
+	return timed
+}
@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/handler"
+	"github.com/saifulwebid/gotodoapp/timestamps"
+)
+
+func TestTimesInJSON(t *testing.T) {
+	svc := timestamps.NewService(&memService{}, timestamps.NewMemoryStore())
+	now := time.Date(2026, 10, 12, 9, 30, 0, 0, time.UTC)
+	svc.Now = func() time.Time { return now }
+	milk, _ := svc.Add("Buy milk", "")
+	now = now.AddDate(0, 0, 7)
+	svc.Add("Buy eggs", "")
+	svc.MarkAsDone(milk)
+
+	h := handler.NewServer(svc)
+	h.Times = svc
+
+	rr := execute(h, httptest.NewRequest("GET", "/v1/1", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"id": 1, "title": "Buy milk", "description": "", "done": true,
+		"created_at": "2026-10-12T09:30:00Z", "updated_at": "2026-10-19T09:30:00Z", "completed_at": "2026-10-19T09:30:00Z"}`, rr.Body.String())
+
+	for _, tc := range []struct {
+		query string
+		code  int
+		ids   []int
+	}{
+		{"", http.StatusOK, []int{1, 2}},
+		{"?since=2026-10-15", http.StatusOK, []int{2}},
+		{"?until=2026-10-12", http.StatusOK, []int{1}},
+		{"?since=2026-10-19&timestamp=completed", http.StatusOK, []int{1}},
+		{"?since=2026-10-19&timestamp=completed&done=false", http.StatusOK, []int{}},
+		{"?since=last+week", http.StatusBadRequest, nil},
+		{"?since=2026-10-15&timestamp=deleted", http.StatusBadRequest, nil},
+	} {
+		rr := execute(h, httptest.NewRequest("GET", "/v1/"+tc.query, nil))
+		assert.Equal(t, tc.code, rr.Code, tc.query)
+		if tc.code != http.StatusOK {
+			continue
+		}
+
+		var todos []*timestamps.Todo
+		if assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &todos)) {
+			ids := []int{}
+			for _, todo := range todos {
+				ids = append(ids, todo.ID)
+				assert.NotNil(t, todo.CreatedAt)
+			}
+			assert.Equal(t, tc.ids, ids, tc.query)
+		}
+	}
+
+	// Without Times, the Todos carry no timestamp and can not be filtered.
+	h = handler.NewServer(svc)
+	rr = execute(h, httptest.NewRequest("GET", "/v1/", nil))
+	assert.NotContains(t, rr.Body.String(), "created_at")
+	rr = execute(h, httptest.NewRequest("GET", "/v1/?since=7d", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+}
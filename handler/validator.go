@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type apiSchema struct {
+	Ref        string                `json:"$ref"`
+	Type       string                `json:"type"`
+	Enum       []interface{}         `json:"enum"`
+	Required   []string              `json:"required"`
+	Properties map[string]*apiSchema `json:"properties"`
+	Items      *apiSchema            `json:"items"`
+}
+
+type apiParameter struct {
+	Ref      string     `json:"$ref"`
+	Name     string     `json:"name"`
+	In       string     `json:"in"`
+	Required bool       `json:"required"`
+	Schema   *apiSchema `json:"schema"`
+}
+
+type apiOperation struct {
+	Parameters  []*apiParameter `json:"parameters"`
+	RequestBody *struct {
+		Required bool `json:"required"`
+		Content  map[string]struct {
+			Schema *apiSchema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+type apiSpec struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Parameters map[string]*apiParameter `json:"parameters"`
+		Schemas    map[string]*apiSchema    `json:"schemas"`
+	} `json:"components"`
+}
+
+// RequestValidator is a middleware which rejects requests that do not match
+// the OpenAPI document served by Server. Requests to paths or methods which are
+// not described in the document are passed through as is.
+type RequestValidator struct {
+	next http.Handler
+	spec *apiSpec
+}
+
+// NewRequestValidator returns a RequestValidator which passes valid requests
+// to next.
+func NewRequestValidator(next http.Handler) *RequestValidator {
+	spec := &apiSpec{}
+	if err := json.Unmarshal([]byte(openAPISpec), spec); err != nil {
+		panic(err)
+	}
+
+	return &RequestValidator{next: next, spec: spec}
+}
+
+func (v *RequestValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := v.validate(r); err != nil {
+		respondWithErrorInJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	v.next.ServeHTTP(w, r)
+}
+
+func (v *RequestValidator) validate(r *http.Request) error {
+	template, pathParams, ok := v.matchPath(r.URL.Path)
+	if !ok {
+		// The unversioned routes are an alias to the current version.
+		template, pathParams, ok = v.matchPath("/" + currentVersion + r.URL.Path)
+		if !ok {
+			return nil
+		}
+	}
+
+	item := v.spec.Paths[template]
+	rawOp, ok := item[strings.ToLower(r.Method)]
+	if !ok {
+		return nil
+	}
+
+	op := &apiOperation{}
+	if err := json.Unmarshal(rawOp, op); err != nil {
+		return nil
+	}
+
+	params := op.Parameters
+	if rawParams, ok := item["parameters"]; ok {
+		var shared []*apiParameter
+		if err := json.Unmarshal(rawParams, &shared); err == nil {
+			params = append(shared, params...)
+		}
+	}
+
+	query := r.URL.Query()
+	for _, p := range params {
+		p = v.resolveParameter(p)
+
+		var value string
+		var present bool
+		switch p.In {
+		case "path":
+			value, present = pathParams[p.Name]
+		case "query":
+			_, present = query[p.Name]
+			value = query.Get(p.Name)
+		default:
+			continue
+		}
+
+		if !present {
+			if p.Required {
+				return fmt.Errorf("%s parameter %q is required", p.In, p.Name)
+			}
+			continue
+		}
+
+		if err := v.validateParameter(p, value); err != nil {
+			return err
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+
+	media, ok := op.RequestBody.Content["application/json"]
+	if !ok || media.Schema == nil {
+		return nil
+	}
+
+	body := []byte{}
+	if r.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return fmt.Errorf("cannot read request body")
+		}
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			return fmt.Errorf("request body is required")
+		}
+		return nil
+	}
+
+	var payload interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return fmt.Errorf("Invalid request payload")
+	}
+
+	return v.validateValue("body", media.Schema, payload)
+}
+
+// matchPath finds the path template in the document which matches path,
+// preferring templates with fewer parameters. It returns the template and the
+// values of its path parameters.
+func (v *RequestValidator) matchPath(path string) (string, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	var bestTemplate string
+	var bestParams map[string]string
+	for template := range v.spec.Paths {
+		templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+		if len(templateSegments) != len(segments) {
+			continue
+		}
+
+		params := map[string]string{}
+		matched := true
+		for i, ts := range templateSegments {
+			if strings.HasPrefix(ts, "{") && strings.HasSuffix(ts, "}") && segments[i] != "" {
+				params[ts[1:len(ts)-1]] = segments[i]
+				continue
+			}
+
+			if ts != segments[i] {
+				matched = false
+				break
+			}
+		}
+
+		if matched && (bestParams == nil || len(params) < len(bestParams)) {
+			bestTemplate, bestParams = template, params
+		}
+	}
+
+	return bestTemplate, bestParams, bestParams != nil
+}
+
+func (v *RequestValidator) resolveParameter(p *apiParameter) *apiParameter {
+	if p.Ref == "" {
+		return p
+	}
+
+	if resolved, ok := v.spec.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]; ok {
+		return resolved
+	}
+
+	return p
+}
+
+func (v *RequestValidator) resolveSchema(s *apiSchema) *apiSchema {
+	if s.Ref == "" {
+		return s
+	}
+
+	if resolved, ok := v.spec.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]; ok {
+		return resolved
+	}
+
+	return s
+}
+
+func (v *RequestValidator) validateParameter(p *apiParameter, value string) error {
+	if p.Schema == nil {
+		return nil
+	}
+
+	schema := v.resolveSchema(p.Schema)
+	name := fmt.Sprintf("%s parameter %q", p.In, p.Name)
+
+	switch schema.Type {
+	case "integer":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer", name)
+		}
+		return v.validateValue(name, schema, json.Number(strconv.Itoa(n)))
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a boolean", name)
+		}
+		return v.validateValue(name, schema, b)
+	}
+
+	return v.validateValue(name, schema, value)
+}
+
+// validateValue checks a value decoded from JSON against schema. Only the
+// subset of JSON Schema used by the document is supported.
+func (v *RequestValidator) validateValue(name string, schema *apiSchema, value interface{}) error {
+	schema = v.resolveSchema(schema)
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an object", name)
+		}
+
+		for _, key := range schema.Required {
+			if _, ok := obj[key]; !ok {
+				return fmt.Errorf("%s.%s is required", name, key)
+			}
+		}
+
+		for key, property := range schema.Properties {
+			if propertyValue, ok := obj[key]; ok {
+				if err := v.validateValue(name+"."+key, property, propertyValue); err != nil {
+					return err
+				}
+			}
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an array", name)
+		}
+
+		if schema.Items != nil {
+			for i, item := range arr {
+				if err := v.validateValue(fmt.Sprintf("%s[%d]", name, i), schema.Items, item); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s must be a string", name)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", name)
+		}
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s must be an integer", name)
+		}
+		if _, err := n.Int64(); err != nil {
+			return fmt.Errorf("%s must be an integer", name)
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s must be one of %v", name, schema.Enum)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+)
+
+// currentVersion is the API version served under its own prefix and by the
+// deprecated unversioned routes.
+const currentVersion = "v1"
+
+// legacySunset is the date after which the unversioned routes may be removed,
+// formatted as an HTTP date.
+const legacySunset = "Mon, 01 Mar 2027 00:00:00 GMT"
+
+// versionMediaType matches the vendor media type used to negotiate the API
+// version through the Accept header, e.g. "application/vnd.gotodo.v1+json".
+var versionMediaType = regexp.MustCompile(`application/vnd\.gotodo\.(v[0-9]+)\+json`)
+
+// acceptedVersion returns the API version requested in the Accept header of r,
+// if any.
+func acceptedVersion(r *http.Request) (string, bool) {
+	for _, accept := range r.Header["Accept"] {
+		if m := versionMediaType.FindStringSubmatch(accept); m != nil {
+			return m[1], true
+		}
+	}
+
+	return "", false
+}
+
+// versioned serves requests to "/v1/..." routes. It refuses requests which
+// negotiate another version through the Accept header.
+func (s *Server) versioned() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		if version, ok := acceptedVersion(r); ok && version != currentVersion {
+			respondWithErrorInJSON(w, http.StatusNotAcceptable, errors.New("unsupported API version "+version))
+			return
+		}
+
+		s.Router.ServeHTTP(w, r)
+	})
+}
+
+// negotiated serves requests to unversioned routes. A request which negotiates
+// a version through the Accept header is served by that version; any other
+// request is served by the deprecated alias to the current version.
+func (s *Server) negotiated() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+
+		version, ok := acceptedVersion(r)
+		if !ok {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Sunset", legacySunset)
+			w.Header().Set("Link", `</`+currentVersion+r.URL.Path+`>; rel="successor-version"`)
+
+			s.legacy.ServeHTTP(w, r)
+			return
+		}
+
+		if version != currentVersion {
+			respondWithErrorInJSON(w, http.StatusNotAcceptable, errors.New("unsupported API version "+version))
+			return
+		}
+
+		s.legacy.ServeHTTP(w, r)
+	})
+}
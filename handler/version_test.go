@@ -0,0 +1,72 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/handler"
+)
+
+func TestVersioning(t *testing.T) {
+	svc := newExampleService()
+	h := handler.NewServer(svc)
+
+	t.Run("versioned route", func(t *testing.T) {
+		svc.GetInvoked = 0
+
+		rr := execute(h, httptest.NewRequest("GET", "/v1/1", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, 1, svc.GetInvoked)
+		assert.Empty(t, rr.Header().Get("Deprecation"))
+	})
+
+	t.Run("deprecated alias", func(t *testing.T) {
+		svc.GetInvoked = 0
+
+		rr := execute(h, httptest.NewRequest("GET", "/1", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, 1, svc.GetInvoked)
+		assert.Equal(t, "true", rr.Header().Get("Deprecation"))
+		assert.NotEmpty(t, rr.Header().Get("Sunset"))
+		assert.Equal(t, `</v1/1>; rel="successor-version"`, rr.Header().Get("Link"))
+	})
+
+	t.Run("negotiated version", func(t *testing.T) {
+		svc.GetInvoked = 0
+
+		req := httptest.NewRequest("GET", "/1", nil)
+		req.Header.Set("Accept", "application/vnd.gotodo.v1+json")
+		rr := execute(h, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, 1, svc.GetInvoked)
+		assert.Empty(t, rr.Header().Get("Deprecation"))
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		svc.GetInvoked = 0
+
+		req := httptest.NewRequest("GET", "/1", nil)
+		req.Header.Set("Accept", "application/vnd.gotodo.v2+json")
+		rr := execute(h, req)
+
+		assert.Equal(t, http.StatusNotAcceptable, rr.Code)
+		assert.Equal(t, 0, svc.GetInvoked)
+	})
+
+	t.Run("mismatched version", func(t *testing.T) {
+		svc.GetInvoked = 0
+
+		req := httptest.NewRequest("GET", "/v1/1", nil)
+		req.Header.Set("Accept", "application/vnd.gotodo.v2+json")
+		rr := execute(h, req)
+
+		assert.Equal(t, http.StatusNotAcceptable, rr.Code)
+		assert.Equal(t, 0, svc.GetInvoked)
+	})
+}
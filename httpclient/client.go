@@ -0,0 +1,292 @@
+// Package httpclient provides a gotodo.Service calling the Todo API of a
+// gotodoserver over HTTP.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/stats"
+	"github.com/saifulwebid/gotodoapp/timestamps"
+)
+
+// apiVersion is the version of the Todo API the Client calls.
+const apiVersion = "v1"
+
+// DefaultClientTimeout is the default deadline of the requests made by a
+// Client.
+const DefaultClientTimeout = 10 * time.Second
+
+// Client is a gotodo.Service which calls the Todo API of a gotodoserver.
+type Client struct {
+	base *url.URL
+	ctx  context.Context
+
+	// HTTPClient makes the requests. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Token, if set, is sent as a bearer token with every request. It is
+	// only sent over HTTPS, or over plain HTTP to the local host.
+	Token string
+
+	// Timeout is the deadline of every request. If zero, requests have no
+	// deadline besides the one of the bound context.
+	Timeout time.Duration
+
+	// OnError is called with the errors of the calls which can not return them
+	// through gotodo.Service, i.e. GetAll, GetPending, GetFinished and
+	// DeleteFinished. Those return an empty result on error.
+	OnError func(method string, err error)
+}
+
+// NewClient returns a Client calling the server at baseURL, e.g.
+// "https://todo.example.com".
+func NewClient(baseURL string) (*Client, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL scheme %q", base.Scheme)
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/") + "/" + apiVersion + "/"
+
+	return &Client{
+		base:    base,
+		ctx:     context.Background(),
+		Timeout: DefaultClientTimeout,
+	}, nil
+}
+
+// tokenAllowed tells whether a token may be sent to u: over HTTPS, or over
+// plain HTTP to the local host, where it can not be eavesdropped.
+func tokenAllowed(u *url.URL) bool {
+	if u.Scheme == "https" {
+		return true
+	}
+
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// WithContext returns a copy of c whose requests are made with ctx.
+func (c *Client) WithContext(ctx context.Context) gotodo.Service {
+	bound := *c
+	bound.ctx = ctx
+
+	return &bound
+}
+
+func (c *Client) context() (context.Context, context.CancelFunc) {
+	if c.Timeout > 0 {
+		return context.WithTimeout(c.ctx, c.Timeout)
+	}
+
+	return context.WithCancel(c.ctx)
+}
+
+// fail reports err to OnError if it is set.
+func (c *Client) fail(method string, err error) {
+	if c.OnError != nil {
+		c.OnError(method, err)
+	}
+}
+
+// do sends a request to path, relative to the API root, with payload encoded
+// as JSON if it is not nil, and decodes the response into result if it is not
+// nil. The error message of an API error is returned as is.
+func (c *Client) do(method, path string, query url.Values, payload, result interface{}) error {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	u := c.base.ResolveReference(&url.URL{Path: path, RawQuery: query.Encode()})
+
+	var body io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		if !tokenAllowed(u) {
+			return fmt.Errorf("refusing to send the token over plain HTTP to %s; use an https URL", u.Host)
+		}
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var apiError struct {
+			Error string `json:"error"`
+		}
+		b, _ := ioutil.ReadAll(resp.Body)
+		if json.Unmarshal(b, &apiError) == nil && apiError.Error != "" {
+			return errors.New(apiError.Error)
+		}
+
+		return fmt.Errorf("%s %s: %s", method, u.Path, resp.Status)
+	}
+
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+
+	return nil
+}
+
+func (c *Client) list(method string, query url.Values) []*gotodo.Todo {
+	todos := []*gotodo.Todo{}
+	if err := c.do("GET", "", query, nil, &todos); err != nil {
+		c.fail(method, err)
+		return []*gotodo.Todo{}
+	}
+
+	return todos
+}
+
+func (c *Client) Get(id int) (*gotodo.Todo, error) {
+	todo := &gotodo.Todo{}
+	if err := c.do("GET", strconv.Itoa(id), nil, nil, todo); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+func (c *Client) GetAll() []*gotodo.Todo {
+	return c.list("GetAll", nil)
+}
+
+func (c *Client) GetPending() []*gotodo.Todo {
+	return c.list("GetPending", url.Values{"done": {"false"}})
+}
+
+func (c *Client) GetFinished() []*gotodo.Todo {
+	return c.list("GetFinished", url.Values{"done": {"true"}})
+}
+
+func (c *Client) Add(title string, description string) (*gotodo.Todo, error) {
+	todo := &gotodo.Todo{}
+	payload := map[string]string{"title": title, "description": description}
+	if err := c.do("POST", "", nil, payload, todo); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// Edit saves the title and the description of todo, then updates todo with
+// what the server returns.
+func (c *Client) Edit(todo *gotodo.Todo) error {
+	payload := map[string]string{"title": todo.Title, "description": todo.Description}
+	return c.do("PATCH", strconv.Itoa(todo.ID), nil, payload, todo)
+}
+
+// MarkAsDone marks todo as done, then updates todo with what the server
+// returns.
+func (c *Client) MarkAsDone(todo *gotodo.Todo) error {
+	return c.do("PUT", strconv.Itoa(todo.ID)+"/done", nil, nil, todo)
+}
+
+func (c *Client) Delete(todo *gotodo.Todo) error {
+	return c.do("DELETE", strconv.Itoa(todo.ID), nil, nil, nil)
+}
+
+func (c *Client) DeleteFinished() {
+	if err := c.do("DELETE", "", url.Values{"done": {"true"}}, nil, nil); err != nil {
+		c.fail("DeleteFinished", err)
+	}
+}
+
+// Times returns the Times of the Todos identified by ids, or of every Todo if
+// ids is empty, as reported by the server. It returns none if the server does
+// not record them.
+func (c *Client) Times(ids ...int) (map[int]timestamps.Times, error) {
+	var todos []*timestamps.Todo
+	if len(ids) == 1 {
+		todo := &timestamps.Todo{}
+		if err := c.do("GET", strconv.Itoa(ids[0]), nil, nil, todo); err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	} else if err := c.do("GET", "", nil, nil, &todos); err != nil {
+		return nil, err
+	}
+
+	wanted := map[int]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	times := map[int]timestamps.Times{}
+	for _, todo := range todos {
+		if todo.Todo == nil || (len(ids) > 0 && !wanted[todo.ID]) {
+			continue
+		}
+		if t := todo.Times(); t != (timestamps.Times{}) {
+			times[todo.ID] = t
+		}
+	}
+
+	return times, nil
+}
+
+// Stats returns the Report of the server, made with opts. opts.Now is not
+// sent, as the server reports at its own time.
+func (c *Client) Stats(opts stats.Options) (*stats.Report, error) {
+	query := url.Values{}
+	if opts.By != "" {
+		query.Set("by", opts.By)
+	}
+	if opts.Last != 0 {
+		query.Set("last", strconv.Itoa(opts.Last))
+	}
+	if opts.Oldest != 0 {
+		query.Set("oldest", strconv.Itoa(opts.Oldest))
+	}
+
+	report := &stats.Report{}
+	if err := c.do("GET", "../stats", query, nil, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
@@ -0,0 +1,271 @@
+package httpclient_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/handler"
+	"github.com/saifulwebid/gotodoapp/httpclient"
+	"github.com/saifulwebid/gotodoapp/stats"
+	"github.com/saifulwebid/gotodoapp/timestamps"
+)
+
+// memService is an in-memory gotodo.Service.
+type memService struct {
+	mu     sync.Mutex
+	todos  []*gotodo.Todo
+	nextID int
+}
+
+func (s *memService) Get(id int) (*gotodo.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, todo := range s.todos {
+		if todo.ID == id {
+			copied := *todo
+			return &copied, nil
+		}
+	}
+
+	return nil, errors.New("not found")
+}
+
+func (s *memService) filter(keep func(todo *gotodo.Todo) bool) []*gotodo.Todo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := []*gotodo.Todo{}
+	for _, todo := range s.todos {
+		if keep(todo) {
+			copied := *todo
+			todos = append(todos, &copied)
+		}
+	}
+
+	return todos
+}
+
+func (s *memService) GetAll() []*gotodo.Todo {
+	return s.filter(func(todo *gotodo.Todo) bool { return true })
+}
+
+func (s *memService) GetPending() []*gotodo.Todo {
+	return s.filter(func(todo *gotodo.Todo) bool { return !todo.Done })
+}
+
+func (s *memService) GetFinished() []*gotodo.Todo {
+	return s.filter(func(todo *gotodo.Todo) bool { return todo.Done })
+}
+
+func (s *memService) Add(title string, description string) (*gotodo.Todo, error) {
+	if title == "" {
+		return nil, errors.New("title must not be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	todo := &gotodo.Todo{ID: s.nextID, Title: title, Description: description}
+	s.todos = append(s.todos, todo)
+
+	copied := *todo
+	return &copied, nil
+}
+
+func (s *memService) update(id int, apply func(todo *gotodo.Todo)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, todo := range s.todos {
+		if todo.ID == id {
+			apply(todo)
+			return nil
+		}
+	}
+
+	return errors.New("not found")
+}
+
+func (s *memService) Edit(todo *gotodo.Todo) error {
+	return s.update(todo.ID, func(stored *gotodo.Todo) {
+		stored.Title = todo.Title
+		stored.Description = todo.Description
+	})
+}
+
+func (s *memService) MarkAsDone(todo *gotodo.Todo) error {
+	todo.Done = true
+	return s.update(todo.ID, func(stored *gotodo.Todo) {
+		stored.Done = true
+	})
+}
+
+func (s *memService) Delete(todo *gotodo.Todo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, stored := range s.todos {
+		if stored.ID == todo.ID {
+			s.todos = append(s.todos[:i], s.todos[i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.New("not found")
+}
+
+func (s *memService) DeleteFinished() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := []*gotodo.Todo{}
+	for _, todo := range s.todos {
+		if !todo.Done {
+			pending = append(pending, todo)
+		}
+	}
+	s.todos = pending
+}
+
+func TestClientToken(t *testing.T) {
+	ts := httptest.NewServer(handler.RequireToken("secret", handler.NewServer(&memService{})))
+	defer ts.Close()
+
+	client, _ := httpclient.NewClient(ts.URL)
+	_, err := client.Add("Buy milk", "")
+	assert.EqualError(t, err, "missing or invalid token")
+
+	client.Token = "secret"
+	_, err = client.Add("Buy milk", "")
+	assert.NoError(t, err)
+
+	// The token is not sent in clear to another host.
+	client, _ = httpclient.NewClient("http://todo.example.com")
+	client.Token = "secret"
+	_, err = client.Get(1)
+	assert.EqualError(t, err, "refusing to send the token over plain HTTP to todo.example.com; use an https URL")
+}
+
+func TestClient(t *testing.T) {
+	var authorization string
+	server := handler.NewServer(&memService{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		server.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	client, err := httpclient.NewClient(ts.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Token = "secret"
+
+	milk, err := client.Add("Buy milk", "Two bottles")
+	if assert.NoError(t, err) {
+		assert.Equal(t, &gotodo.Todo{ID: 1, Title: "Buy milk", Description: "Two bottles"}, milk)
+	}
+	assert.Equal(t, "Bearer secret", authorization)
+	eggs, _ := client.Add("Buy eggs", "")
+
+	milk.Description = "Three bottles"
+	assert.NoError(t, client.Edit(milk))
+	assert.NoError(t, client.MarkAsDone(eggs))
+	assert.True(t, eggs.Done)
+
+	got, err := client.Get(1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Three bottles", got.Description)
+	}
+	_, err = client.Get(42)
+	assert.EqualError(t, err, "Todo not found")
+	_, err = client.Add("", "")
+	assert.EqualError(t, err, "title must not be empty")
+
+	assert.Len(t, client.GetAll(), 2)
+	assert.Equal(t, []*gotodo.Todo{milk}, client.GetPending())
+	assert.Equal(t, []*gotodo.Todo{eggs}, client.GetFinished())
+
+	client.DeleteFinished()
+	assert.Equal(t, []*gotodo.Todo{milk}, client.GetAll())
+	assert.NoError(t, client.Delete(milk))
+	assert.Empty(t, client.GetAll())
+	assert.EqualError(t, client.Delete(milk), "Todo not found")
+}
+
+func TestClientErrors(t *testing.T) {
+	_, err := httpclient.NewClient("ftp://todo.example.com")
+	assert.EqualError(t, err, `unsupported URL scheme "ftp"`)
+
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	client, _ := httpclient.NewClient(ts.URL)
+	var failures []string
+	client.OnError = func(method string, err error) {
+		failures = append(failures, method+": "+err.Error())
+	}
+
+	assert.Empty(t, client.GetAll())
+	client.DeleteFinished()
+	assert.Equal(t, []string{
+		"GetAll: GET /v1/: 404 Not Found",
+		"DeleteFinished: DELETE /v1/: 404 Not Found",
+	}, failures)
+}
+
+func TestClientStats(t *testing.T) {
+	svc := timestamps.NewService(&memService{}, timestamps.NewMemoryStore())
+	svc.Add("Buy milk", "")
+	server := handler.NewServer(svc)
+	server.Times = svc
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	client, _ := httpclient.NewClient(ts.URL)
+
+	report, err := client.Stats(stats.Options{By: stats.Day, Last: 3})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, report.Pending)
+		if assert.Len(t, report.History, 3) {
+			assert.Equal(t, 1, report.History[2].Created)
+		}
+	}
+
+	_, err = client.Stats(stats.Options{Last: -1})
+	assert.EqualError(t, err, "number of intervals must be between 1 and 366")
+}
+
+func TestClientTimes(t *testing.T) {
+	svc := timestamps.NewService(&memService{}, timestamps.NewMemoryStore())
+	now := time.Date(2026, 10, 12, 9, 30, 0, 0, time.UTC)
+	svc.Now = func() time.Time { return now }
+	milk, _ := svc.Add("Buy milk", "")
+	now = now.AddDate(0, 0, 7)
+	svc.Add("Buy eggs", "")
+	svc.MarkAsDone(milk)
+
+	server := handler.NewServer(svc)
+	server.Times = svc
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client, _ := httpclient.NewClient(ts.URL)
+
+	times, err := client.Times()
+	if assert.NoError(t, err) && assert.Len(t, times, 2) {
+		assert.True(t, times[1].CompletedAt.Equal(now))
+	}
+	times, err = client.Times(2)
+	if assert.NoError(t, err) && assert.Len(t, times, 1) {
+		assert.True(t, times[2].CreatedAt.Equal(now))
+	}
+}
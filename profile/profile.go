@@ -0,0 +1,232 @@
+// Package profile manages the named profiles gotodocli connects with, e.g. a
+// personal database, a staging server and production.
+//
+// Profiles are kept in a TOML file, along with the name of the profile in
+// use:
+//
+//	current = "personal"
+//
+//	[profiles.personal]
+//	backend = "database"
+//
+//	  [profiles.personal.db]
+//	  host = "127.0.0.1"
+//	  user = "gotodo"
+//
+//	[profiles.staging]
+//	backend = "http"
+//	url = "https://todo.staging.example.com"
+//	token = "secret"
+//	output = "json"
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pelletier/go-toml"
+)
+
+// Backends of a Profile.
+const (
+	// BackendDatabase connects to the database directly.
+	BackendDatabase = "database"
+	// BackendHTTP calls a gotodoserver.
+	BackendHTTP = "http"
+)
+
+// Output formats of a Profile.
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// DB holds the database settings of a Profile. Unset ones are taken from the
+// configuration.
+type DB struct {
+	Host string `toml:"host,omitempty"`
+	Port int    `toml:"port,omitempty"`
+	Name string `toml:"name,omitempty"`
+	User string `toml:"user,omitempty"`
+	Pass string `toml:"pass,omitempty"`
+}
+
+// Profile is a named set of settings telling gotodocli where the Todos are,
+// and how to print them.
+type Profile struct {
+	Name string `toml:"-"`
+
+	// Backend is either BackendDatabase or BackendHTTP.
+	Backend string `toml:"backend"`
+
+	// DB is used by the database backend.
+	DB DB `toml:"db,omitempty"`
+
+	// URL and Token are used by the HTTP backend. Token is sent as a bearer
+	// token, if set.
+	URL   string `toml:"url,omitempty"`
+	Token string `toml:"token,omitempty"`
+
+	// Output is the default output format, either OutputText or OutputJSON.
+	// It defaults to OutputText.
+	Output string `toml:"output,omitempty"`
+}
+
+// Validate checks that the settings of p are consistent.
+func (p *Profile) Validate() error {
+	switch p.Backend {
+	case BackendDatabase:
+		if p.URL != "" || p.Token != "" {
+			return fmt.Errorf("profile %q: url and token require the %s backend", p.Name, BackendHTTP)
+		}
+	case BackendHTTP:
+		if p.URL == "" {
+			return fmt.Errorf("profile %q: url must be set", p.Name)
+		}
+		if u, err := url.Parse(p.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("profile %q: url %q must be an http or https URL", p.Name, p.URL)
+		}
+		if p.DB != (DB{}) {
+			return fmt.Errorf("profile %q: db settings require the %s backend", p.Name, BackendDatabase)
+		}
+	default:
+		return fmt.Errorf("profile %q: backend %q must be either %s or %s", p.Name, p.Backend, BackendDatabase, BackendHTTP)
+	}
+
+	switch p.Output {
+	case "", OutputText, OutputJSON:
+	default:
+		return fmt.Errorf("profile %q: output %q must be either %s or %s", p.Name, p.Output, OutputText, OutputJSON)
+	}
+
+	return nil
+}
+
+// File is a profiles file.
+type File struct {
+	// Path is where the file is read from and saved to.
+	Path string `toml:"-"`
+
+	// Current names the profile in use, if any.
+	Current string `toml:"current,omitempty"`
+
+	Profiles map[string]*Profile `toml:"profiles"`
+}
+
+// DefaultPath returns the path of the profiles file: GOTODO_PROFILES if it is
+// set, or gotodo/profiles.toml in the user's configuration directory.
+func DefaultPath() (string, error) {
+	if path := os.Getenv("GOTODO_PROFILES"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "gotodo", "profiles.toml"), nil
+}
+
+// Load reads the profiles file at path. A missing file holds no profile.
+//
+// The profiles are only validated by Get, so that a file holding an invalid
+// profile can still be listed and fixed.
+func Load(path string) (*File, error) {
+	f := &File{Path: path}
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		f.Profiles = map[string]*Profile{}
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := toml.Unmarshal(content, f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if f.Profiles == nil {
+		f.Profiles = map[string]*Profile{}
+	}
+
+	for name, p := range f.Profiles {
+		p.Name = name
+	}
+
+	return f, nil
+}
+
+// Save writes f to its path, readable by the user only as profiles may hold
+// passwords and tokens.
+func (f *File) Save() error {
+	content, err := toml.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(f.Path, content, 0600)
+}
+
+// Names returns the names of the profiles, sorted.
+func (f *File) Names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Get returns the profile called name, or an error if it does not exist or
+// is invalid.
+func (f *File) Get(name string) (*Profile, error) {
+	p, ok := f.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q does not exist", name)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Path, err)
+	}
+
+	return p, nil
+}
+
+// Add adds p, which must be valid and not exist yet.
+func (f *File) Add(p *Profile) error {
+	if p.Name == "" {
+		return errors.New("profile name must not be empty")
+	}
+	if _, ok := f.Profiles[p.Name]; ok {
+		return fmt.Errorf("profile %q already exists", p.Name)
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	f.Profiles[p.Name] = p
+
+	return nil
+}
+
+// Use makes the profile called name the current one.
+func (f *File) Use(name string) error {
+	if _, err := f.Get(name); err != nil {
+		return err
+	}
+
+	f.Current = name
+
+	return nil
+}
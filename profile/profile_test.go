@@ -0,0 +1,99 @@
+package profile_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/profile"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gotodo", "profiles.toml")
+
+	f, err := profile.Load(path)
+	if assert.NoError(t, err) {
+		assert.Empty(t, f.Profiles)
+	}
+
+	assert.NoError(t, f.Add(&profile.Profile{
+		Name:    "personal",
+		Backend: profile.BackendDatabase,
+		DB:      profile.DB{Host: "127.0.0.1", Port: 3307},
+	}))
+	assert.NoError(t, f.Add(&profile.Profile{
+		Name:    "staging",
+		Backend: profile.BackendHTTP,
+		URL:     "https://todo.staging.example.com",
+		Token:   "secret",
+		Output:  profile.OutputJSON,
+	}))
+	assert.NoError(t, f.Use("staging"))
+	assert.NoError(t, f.Save())
+
+	info, err := os.Stat(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	loaded, err := profile.Load(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, f, loaded)
+		assert.Equal(t, []string{"personal", "staging"}, loaded.Names())
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.toml")
+	if err := ioutil.WriteFile(path, []byte("profiles = 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := profile.Load(path)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "profiles.toml")
+	}
+}
+
+func TestGetInvalid(t *testing.T) {
+	for content, message := range map[string]string{
+		"":                                      `profile "prod" does not exist`,
+		"[profiles.prod]\nbackend = \"grpc\"\n": `profile "prod": backend "grpc" must be either database or http`,
+		"[profiles.prod]\nbackend = \"http\"\n": `profile "prod": url must be set`,
+		"[profiles.prod]\nbackend = \"http\"\nurl = \"example.com\"\n": `url "example.com" must be an http or https URL`,
+		"[profiles.prod]\nbackend = \"database\"\noutput = \"yaml\"\n": `output "yaml" must be either text or json`,
+	} {
+		path := filepath.Join(t.TempDir(), "profiles.toml")
+		content = "current = \"prod\"\n\n[profiles.personal]\nbackend = \"database\"\n\n" + content
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		// The other profiles are still usable.
+		f, err := profile.Load(path)
+		if !assert.NoError(t, err, content) {
+			continue
+		}
+		assert.NoError(t, f.Use("personal"), content)
+
+		_, err = f.Get("prod")
+		if assert.Error(t, err, content) {
+			assert.Contains(t, err.Error(), message, content)
+		}
+		assert.Error(t, f.Use("prod"), content)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	f, _ := profile.Load(filepath.Join(t.TempDir(), "profiles.toml"))
+
+	assert.NoError(t, f.Add(&profile.Profile{Name: "personal", Backend: profile.BackendDatabase}))
+	assert.EqualError(t, f.Add(&profile.Profile{Name: "personal", Backend: profile.BackendDatabase}), `profile "personal" already exists`)
+	assert.EqualError(t, f.Add(&profile.Profile{Backend: profile.BackendDatabase}), "profile name must not be empty")
+	assert.EqualError(t, f.Add(&profile.Profile{Name: "prod", Backend: profile.BackendDatabase, URL: "https://example.com"}),
+		`profile "prod": url and token require the http backend`)
+	assert.EqualError(t, f.Use("prod"), `profile "prod" does not exist`)
+}
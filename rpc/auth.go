@@ -0,0 +1,38 @@
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tokenMatches tells whether authorization carries token as a bearer token.
+func tokenMatches(authorization, token string) bool {
+	const prefix = "Bearer "
+	if len(authorization) < len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(authorization[len(prefix):]), []byte(token)) == 1
+}
+
+// RequireToken returns a grpc.UnaryServerInterceptor which refuses the calls
+// not carrying token as a bearer token in their "authorization" metadata, as
+// handler.RequireToken does for HTTP requests.
+func RequireToken(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handle grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, authorization := range md.Get("authorization") {
+			if tokenMatches(authorization, token) {
+				return handle(ctx, req)
+			}
+		}
+
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid token")
+	}
+}
@@ -0,0 +1,207 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/rpc/gotodopb"
+)
+
+// DefaultTimeout is the default deadline of the calls made by a Client.
+const DefaultTimeout = 10 * time.Second
+
+// Client is a gotodo.Service which calls a gotodo gRPC server.
+type Client struct {
+	client gotodopb.TodoServiceClient
+	ctx    context.Context
+
+	// Timeout is the deadline of every call. If zero, calls have no deadline
+	// besides the one of the bound context.
+	Timeout time.Duration
+
+	// OnError is called with the errors of the calls which can not return them
+	// through gotodo.Service, i.e. GetAll, GetPending, GetFinished and
+	// DeleteFinished. Those return an empty result on error.
+	OnError func(method string, err error)
+}
+
+// NewClient returns a Client calling the server on conn.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{
+		client:  gotodopb.NewTodoServiceClient(conn),
+		ctx:     context.Background(),
+		Timeout: DefaultTimeout,
+	}
+}
+
+// WithContext returns a copy of c whose calls are made with ctx.
+func (c *Client) WithContext(ctx context.Context) gotodo.Service {
+	bound := *c
+	bound.ctx = ctx
+
+	return &bound
+}
+
+func (c *Client) context() (context.Context, context.CancelFunc) {
+	if c.Timeout > 0 {
+		return context.WithTimeout(c.ctx, c.Timeout)
+	}
+
+	return context.WithCancel(c.ctx)
+}
+
+// fail reports err to OnError if it is set.
+func (c *Client) fail(method string, err error) {
+	if c.OnError != nil {
+		c.OnError(method, err)
+	}
+}
+
+// fromStatus returns an error carrying the message of the gRPC status of err.
+func fromStatus(err error) error {
+	if s, ok := status.FromError(err); ok {
+		return errors.New(s.Message())
+	}
+
+	return err
+}
+
+func fromProto(todo *gotodopb.Todo) *gotodo.Todo {
+	return &gotodo.Todo{
+		ID:          int(todo.GetId()),
+		Title:       todo.GetTitle(),
+		Description: todo.GetDescription(),
+		Done:        todo.GetDone(),
+	}
+}
+
+func fromProtoList(list *gotodopb.TodoList) []*gotodo.Todo {
+	todos := make([]*gotodo.Todo, 0, len(list.GetTodos()))
+	for _, todo := range list.GetTodos() {
+		todos = append(todos, fromProto(todo))
+	}
+
+	return todos
+}
+
+func (c *Client) Get(id int) (*gotodo.Todo, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	todo, err := c.client.Get(ctx, &gotodopb.GetRequest{Id: int64(id)})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+
+	return fromProto(todo), nil
+}
+
+func (c *Client) GetAll() []*gotodo.Todo {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	list, err := c.client.GetAll(ctx, &gotodopb.GetAllRequest{})
+	if err != nil {
+		c.fail("GetAll", fromStatus(err))
+		return []*gotodo.Todo{}
+	}
+
+	return fromProtoList(list)
+}
+
+func (c *Client) GetPending() []*gotodo.Todo {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	list, err := c.client.GetPending(ctx, &gotodopb.GetPendingRequest{})
+	if err != nil {
+		c.fail("GetPending", fromStatus(err))
+		return []*gotodo.Todo{}
+	}
+
+	return fromProtoList(list)
+}
+
+func (c *Client) GetFinished() []*gotodo.Todo {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	list, err := c.client.GetFinished(ctx, &gotodopb.GetFinishedRequest{})
+	if err != nil {
+		c.fail("GetFinished", fromStatus(err))
+		return []*gotodo.Todo{}
+	}
+
+	return fromProtoList(list)
+}
+
+func (c *Client) Add(title string, description string) (*gotodo.Todo, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	todo, err := c.client.Add(ctx, &gotodopb.AddRequest{Title: title, Description: description})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+
+	return fromProto(todo), nil
+}
+
+// Edit saves the title and the description of todo, then updates todo with
+// what the server returns.
+func (c *Client) Edit(todo *gotodo.Todo) error {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	edited, err := c.client.Edit(ctx, &gotodopb.EditRequest{
+		Id:          int64(todo.ID),
+		Title:       todo.Title,
+		Description: &todo.Description,
+	})
+	if err != nil {
+		return fromStatus(err)
+	}
+
+	*todo = *fromProto(edited)
+	return nil
+}
+
+// MarkAsDone marks todo as done, then updates todo with what the server
+// returns.
+func (c *Client) MarkAsDone(todo *gotodo.Todo) error {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	done, err := c.client.MarkAsDone(ctx, &gotodopb.MarkAsDoneRequest{Id: int64(todo.ID)})
+	if err != nil {
+		return fromStatus(err)
+	}
+
+	*todo = *fromProto(done)
+	return nil
+}
+
+func (c *Client) Delete(todo *gotodo.Todo) error {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	if _, err := c.client.Delete(ctx, &gotodopb.DeleteRequest{Id: int64(todo.ID)}); err != nil {
+		return fromStatus(err)
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteFinished() {
+	ctx, cancel := c.context()
+	defer cancel()
+
+	if _, err := c.client.DeleteFinished(ctx, &gotodopb.DeleteFinishedRequest{}); err != nil {
+		c.fail("DeleteFinished", fromStatus(err))
+	}
+}
@@ -0,0 +1,5 @@
+// Package gotodopb holds the protocol buffers messages and the gRPC stubs of
+// the gotodo service, generated from gotodo.proto.
+package gotodopb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative gotodo.proto
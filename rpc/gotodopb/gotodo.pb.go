@@ -0,0 +1,722 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v3.5.1-go
+// source: gotodo.proto
+
+package gotodopb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Todo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Done          bool                   `protobuf:"varint,4,opt,name=done,proto3" json:"done,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Todo) Reset() {
+	*x = Todo{}
+	mi := &file_gotodo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Todo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Todo) ProtoMessage() {}
+
+func (x *Todo) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Todo.ProtoReflect.Descriptor instead.
+func (*Todo) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Todo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Todo) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Todo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Todo) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+type TodoList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Todos         []*Todo                `protobuf:"bytes,1,rep,name=todos,proto3" json:"todos,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TodoList) Reset() {
+	*x = TodoList{}
+	mi := &file_gotodo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoList) ProtoMessage() {}
+
+func (x *TodoList) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoList.ProtoReflect.Descriptor instead.
+func (*TodoList) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TodoList) GetTodos() []*Todo {
+	if x != nil {
+		return x.Todos
+	}
+	return nil
+}
+
+type GetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	mi := &file_gotodo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllRequest) Reset() {
+	*x = GetAllRequest{}
+	mi := &file_gotodo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllRequest) ProtoMessage() {}
+
+func (x *GetAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllRequest.ProtoReflect.Descriptor instead.
+func (*GetAllRequest) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{3}
+}
+
+type GetPendingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPendingRequest) Reset() {
+	*x = GetPendingRequest{}
+	mi := &file_gotodo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPendingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPendingRequest) ProtoMessage() {}
+
+func (x *GetPendingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPendingRequest.ProtoReflect.Descriptor instead.
+func (*GetPendingRequest) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{4}
+}
+
+type GetFinishedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFinishedRequest) Reset() {
+	*x = GetFinishedRequest{}
+	mi := &file_gotodo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFinishedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFinishedRequest) ProtoMessage() {}
+
+func (x *GetFinishedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFinishedRequest.ProtoReflect.Descriptor instead.
+func (*GetFinishedRequest) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{5}
+}
+
+type AddRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRequest) Reset() {
+	*x = AddRequest{}
+	mi := &file_gotodo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRequest) ProtoMessage() {}
+
+func (x *AddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRequest.ProtoReflect.Descriptor instead.
+func (*AddRequest) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AddRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *AddRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type EditRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	// description is left unchanged if it is not set.
+	Description   *string `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditRequest) Reset() {
+	*x = EditRequest{}
+	mi := &file_gotodo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditRequest) ProtoMessage() {}
+
+func (x *EditRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditRequest.ProtoReflect.Descriptor instead.
+func (*EditRequest) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *EditRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EditRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *EditRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+type MarkAsDoneRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkAsDoneRequest) Reset() {
+	*x = MarkAsDoneRequest{}
+	mi := &file_gotodo_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkAsDoneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkAsDoneRequest) ProtoMessage() {}
+
+func (x *MarkAsDoneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkAsDoneRequest.ProtoReflect.Descriptor instead.
+func (*MarkAsDoneRequest) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MarkAsDoneRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_gotodo_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_gotodo_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{10}
+}
+
+type DeleteFinishedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFinishedRequest) Reset() {
+	*x = DeleteFinishedRequest{}
+	mi := &file_gotodo_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFinishedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFinishedRequest) ProtoMessage() {}
+
+func (x *DeleteFinishedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFinishedRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFinishedRequest) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{11}
+}
+
+type DeleteFinishedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFinishedResponse) Reset() {
+	*x = DeleteFinishedResponse{}
+	mi := &file_gotodo_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFinishedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFinishedResponse) ProtoMessage() {}
+
+func (x *DeleteFinishedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gotodo_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFinishedResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFinishedResponse) Descriptor() ([]byte, []int) {
+	return file_gotodo_proto_rawDescGZIP(), []int{12}
+}
+
+var File_gotodo_proto protoreflect.FileDescriptor
+
+const file_gotodo_proto_rawDesc = "" +
+	"\n" +
+	"\fgotodo.proto\x12\tgotodo.v1\"b\n" +
+	"\x04Todo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04done\x18\x04 \x01(\bR\x04done\"1\n" +
+	"\bTodoList\x12%\n" +
+	"\x05todos\x18\x01 \x03(\v2\x0f.gotodo.v1.TodoR\x05todos\"\x1c\n" +
+	"\n" +
+	"GetRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x0f\n" +
+	"\rGetAllRequest\"\x13\n" +
+	"\x11GetPendingRequest\"\x14\n" +
+	"\x12GetFinishedRequest\"D\n" +
+	"\n" +
+	"AddRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\"j\n" +
+	"\vEditRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12%\n" +
+	"\vdescription\x18\x03 \x01(\tH\x00R\vdescription\x88\x01\x01B\x0e\n" +
+	"\f_description\"#\n" +
+	"\x11MarkAsDoneRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x1f\n" +
+	"\rDeleteRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x10\n" +
+	"\x0eDeleteResponse\"\x17\n" +
+	"\x15DeleteFinishedRequest\"\x18\n" +
+	"\x16DeleteFinishedResponse2\xac\x04\n" +
+	"\vTodoService\x12-\n" +
+	"\x03Get\x12\x15.gotodo.v1.GetRequest\x1a\x0f.gotodo.v1.Todo\x127\n" +
+	"\x06GetAll\x12\x18.gotodo.v1.GetAllRequest\x1a\x13.gotodo.v1.TodoList\x12?\n" +
+	"\n" +
+	"GetPending\x12\x1c.gotodo.v1.GetPendingRequest\x1a\x13.gotodo.v1.TodoList\x12A\n" +
+	"\vGetFinished\x12\x1d.gotodo.v1.GetFinishedRequest\x1a\x13.gotodo.v1.TodoList\x12-\n" +
+	"\x03Add\x12\x15.gotodo.v1.AddRequest\x1a\x0f.gotodo.v1.Todo\x12/\n" +
+	"\x04Edit\x12\x16.gotodo.v1.EditRequest\x1a\x0f.gotodo.v1.Todo\x12;\n" +
+	"\n" +
+	"MarkAsDone\x12\x1c.gotodo.v1.MarkAsDoneRequest\x1a\x0f.gotodo.v1.Todo\x12=\n" +
+	"\x06Delete\x12\x18.gotodo.v1.DeleteRequest\x1a\x19.gotodo.v1.DeleteResponse\x12U\n" +
+	"\x0eDeleteFinished\x12 .gotodo.v1.DeleteFinishedRequest\x1a!.gotodo.v1.DeleteFinishedResponseB/Z-github.com/saifulwebid/gotodoapp/rpc/gotodopbb\x06proto3"
+
+var (
+	file_gotodo_proto_rawDescOnce sync.Once
+	file_gotodo_proto_rawDescData []byte
+)
+
+func file_gotodo_proto_rawDescGZIP() []byte {
+	file_gotodo_proto_rawDescOnce.Do(func() {
+		file_gotodo_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_gotodo_proto_rawDesc), len(file_gotodo_proto_rawDesc)))
+	})
+	return file_gotodo_proto_rawDescData
+}
+
+var file_gotodo_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_gotodo_proto_goTypes = []any{
+	(*Todo)(nil),                   // 0: gotodo.v1.Todo
+	(*TodoList)(nil),               // 1: gotodo.v1.TodoList
+	(*GetRequest)(nil),             // 2: gotodo.v1.GetRequest
+	(*GetAllRequest)(nil),          // 3: gotodo.v1.GetAllRequest
+	(*GetPendingRequest)(nil),      // 4: gotodo.v1.GetPendingRequest
+	(*GetFinishedRequest)(nil),     // 5: gotodo.v1.GetFinishedRequest
+	(*AddRequest)(nil),             // 6: gotodo.v1.AddRequest
+	(*EditRequest)(nil),            // 7: gotodo.v1.EditRequest
+	(*MarkAsDoneRequest)(nil),      // 8: gotodo.v1.MarkAsDoneRequest
+	(*DeleteRequest)(nil),          // 9: gotodo.v1.DeleteRequest
+	(*DeleteResponse)(nil),         // 10: gotodo.v1.DeleteResponse
+	(*DeleteFinishedRequest)(nil),  // 11: gotodo.v1.DeleteFinishedRequest
+	(*DeleteFinishedResponse)(nil), // 12: gotodo.v1.DeleteFinishedResponse
+}
+var file_gotodo_proto_depIdxs = []int32{
+	0,  // 0: gotodo.v1.TodoList.todos:type_name -> gotodo.v1.Todo
+	2,  // 1: gotodo.v1.TodoService.Get:input_type -> gotodo.v1.GetRequest
+	3,  // 2: gotodo.v1.TodoService.GetAll:input_type -> gotodo.v1.GetAllRequest
+	4,  // 3: gotodo.v1.TodoService.GetPending:input_type -> gotodo.v1.GetPendingRequest
+	5,  // 4: gotodo.v1.TodoService.GetFinished:input_type -> gotodo.v1.GetFinishedRequest
+	6,  // 5: gotodo.v1.TodoService.Add:input_type -> gotodo.v1.AddRequest
+	7,  // 6: gotodo.v1.TodoService.Edit:input_type -> gotodo.v1.EditRequest
+	8,  // 7: gotodo.v1.TodoService.MarkAsDone:input_type -> gotodo.v1.MarkAsDoneRequest
+	9,  // 8: gotodo.v1.TodoService.Delete:input_type -> gotodo.v1.DeleteRequest
+	11, // 9: gotodo.v1.TodoService.DeleteFinished:input_type -> gotodo.v1.DeleteFinishedRequest
+	0,  // 10: gotodo.v1.TodoService.Get:output_type -> gotodo.v1.Todo
+	1,  // 11: gotodo.v1.TodoService.GetAll:output_type -> gotodo.v1.TodoList
+	1,  // 12: gotodo.v1.TodoService.GetPending:output_type -> gotodo.v1.TodoList
+	1,  // 13: gotodo.v1.TodoService.GetFinished:output_type -> gotodo.v1.TodoList
+	0,  // 14: gotodo.v1.TodoService.Add:output_type -> gotodo.v1.Todo
+	0,  // 15: gotodo.v1.TodoService.Edit:output_type -> gotodo.v1.Todo
+	0,  // 16: gotodo.v1.TodoService.MarkAsDone:output_type -> gotodo.v1.Todo
+	10, // 17: gotodo.v1.TodoService.Delete:output_type -> gotodo.v1.DeleteResponse
+	12, // 18: gotodo.v1.TodoService.DeleteFinished:output_type -> gotodo.v1.DeleteFinishedResponse
+	10, // [10:19] is the sub-list for method output_type
+	1,  // [1:10] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_gotodo_proto_init() }
+func file_gotodo_proto_init() {
+	if File_gotodo_proto != nil {
+		return
+	}
+	file_gotodo_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gotodo_proto_rawDesc), len(file_gotodo_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gotodo_proto_goTypes,
+		DependencyIndexes: file_gotodo_proto_depIdxs,
+		MessageInfos:      file_gotodo_proto_msgTypes,
+	}.Build()
+	File_gotodo_proto = out.File
+	file_gotodo_proto_goTypes = nil
+	file_gotodo_proto_depIdxs = nil
+}
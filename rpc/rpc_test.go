@@ -0,0 +1,322 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/handler"
+	"github.com/saifulwebid/gotodoapp/rpc"
+	"github.com/saifulwebid/gotodoapp/rpc/gotodopb"
+)
+
+// memService is an in-memory gotodo.Service.
+type memService struct {
+	mu     sync.Mutex
+	todos  []*gotodo.Todo
+	nextID int
+
+	// addErr, if set, is returned by Add, as if the storage failed.
+	addErr error
+}
+
+func (s *memService) Get(id int) (*gotodo.Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, todo := range s.todos {
+		if todo.ID == id {
+			copied := *todo
+			return &copied, nil
+		}
+	}
+
+	return nil, errors.New("not found")
+}
+
+func (s *memService) filter(keep func(todo *gotodo.Todo) bool) []*gotodo.Todo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := []*gotodo.Todo{}
+	for _, todo := range s.todos {
+		if keep(todo) {
+			copied := *todo
+			todos = append(todos, &copied)
+		}
+	}
+
+	return todos
+}
+
+func (s *memService) GetAll() []*gotodo.Todo {
+	return s.filter(func(todo *gotodo.Todo) bool { return true })
+}
+
+func (s *memService) GetPending() []*gotodo.Todo {
+	return s.filter(func(todo *gotodo.Todo) bool { return !todo.Done })
+}
+
+func (s *memService) GetFinished() []*gotodo.Todo {
+	return s.filter(func(todo *gotodo.Todo) bool { return todo.Done })
+}
+
+func (s *memService) Add(title string, description string) (*gotodo.Todo, error) {
+	if title == "" {
+		return nil, errors.New("title must not be empty")
+	}
+	if s.addErr != nil {
+		return nil, s.addErr
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	todo := &gotodo.Todo{ID: s.nextID, Title: title, Description: description}
+	s.todos = append(s.todos, todo)
+
+	copied := *todo
+	return &copied, nil
+}
+
+func (s *memService) update(id int, apply func(todo *gotodo.Todo)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, todo := range s.todos {
+		if todo.ID == id {
+			apply(todo)
+			return nil
+		}
+	}
+
+	return errors.New("not found")
+}
+
+func (s *memService) Edit(todo *gotodo.Todo) error {
+	return s.update(todo.ID, func(stored *gotodo.Todo) {
+		stored.Title = todo.Title
+		stored.Description = todo.Description
+	})
+}
+
+func (s *memService) MarkAsDone(todo *gotodo.Todo) error {
+	todo.Done = true
+	return s.update(todo.ID, func(stored *gotodo.Todo) {
+		stored.Done = true
+	})
+}
+
+func (s *memService) Delete(todo *gotodo.Todo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, stored := range s.todos {
+		if stored.ID == todo.ID {
+			s.todos = append(s.todos[:i], s.todos[i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.New("not found")
+}
+
+func (s *memService) DeleteFinished() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := []*gotodo.Todo{}
+	for _, todo := range s.todos {
+		if !todo.Done {
+			pending = append(pending, todo)
+		}
+	}
+	s.todos = pending
+}
+
+// dial serves svc over an in-memory connection and returns a client to it.
+func dial(t *testing.T, svc gotodo.Service) *rpc.Client {
+	return rpc.NewClient(dialServer(t, rpc.NewServer(svc)))
+}
+
+// dialServer serves s with opts over an in-memory connection and returns a
+// connection to it.
+func dialServer(t *testing.T, s *rpc.Server, opts ...grpc.ServerOption) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+
+	gs := grpc.NewServer(opts...)
+	s.Register(gs)
+	go gs.Serve(listener)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestClient(t *testing.T) {
+	// Client must be usable wherever a gotodo.Service is.
+	var _ gotodo.Service = &rpc.Client{}
+
+	svc := &memService{}
+	client := dial(t, svc)
+
+	milk, err := client.Add("Buy milk", "Two bottles")
+	assert.NoError(t, err)
+	assert.Equal(t, &gotodo.Todo{ID: 1, Title: "Buy milk", Description: "Two bottles"}, milk)
+
+	_, err = client.Add("", "")
+	assert.EqualError(t, err, "title must not be empty")
+
+	eggs, err := client.Add("Buy eggs", "")
+	assert.NoError(t, err)
+
+	todo, err := client.Get(milk.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, milk, todo)
+
+	_, err = client.Get(42)
+	assert.EqualError(t, err, "Todo not found")
+
+	eggs.Description = "A dozen"
+	assert.NoError(t, client.Edit(eggs))
+	assert.Equal(t, "A dozen", svc.GetAll()[1].Description)
+
+	assert.NoError(t, client.MarkAsDone(milk))
+	assert.True(t, milk.Done)
+
+	assert.Len(t, client.GetAll(), 2)
+	assert.Equal(t, []*gotodo.Todo{eggs}, client.GetPending())
+	assert.Equal(t, []*gotodo.Todo{milk}, client.GetFinished())
+
+	client.DeleteFinished()
+	assert.Equal(t, []*gotodo.Todo{eggs}, client.GetAll())
+
+	assert.NoError(t, client.Delete(eggs))
+	assert.Empty(t, client.GetAll())
+
+	assert.EqualError(t, client.Delete(eggs), "Todo not found")
+}
+
+func TestClientErrors(t *testing.T) {
+	client := dial(t, &memService{})
+
+	var failed []string
+	client.OnError = func(method string, err error) {
+		failed = append(failed, method)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bound := client.WithContext(ctx)
+
+	assert.Empty(t, bound.GetAll())
+	bound.DeleteFinished()
+	_, err := bound.Get(1)
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"GetAll", "DeleteFinished"}, failed)
+}
+
+func TestServer(t *testing.T) {
+	svc := &memService{}
+	client := gotodopb.NewTodoServiceClient(dialServer(t, rpc.NewServer(svc)))
+	ctx := context.Background()
+
+	milk, err := client.Add(ctx, &gotodopb.AddRequest{Title: "Buy milk", Description: "Two bottles"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// A request without description keeps it, as PATCH does.
+	edited, err := client.Edit(ctx, &gotodopb.EditRequest{Id: milk.GetId(), Title: "Buy oat milk"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Two bottles", edited.GetDescription())
+
+	empty := ""
+	edited, err = client.Edit(ctx, &gotodopb.EditRequest{Id: milk.GetId(), Title: "Buy oat milk", Description: &empty})
+	assert.NoError(t, err)
+	assert.Empty(t, edited.GetDescription())
+
+	_, err = client.Add(ctx, &gotodopb.AddRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.Edit(ctx, &gotodopb.EditRequest{Id: milk.GetId()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.Edit(ctx, &gotodopb.EditRequest{Id: 42, Title: "Buy eggs"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	svc.addErr = errors.New("connection refused")
+	_, err = client.Add(ctx, &gotodopb.AddRequest{Title: "Buy eggs"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestServerOnWrite(t *testing.T) {
+	svc := &memService{}
+	api := handler.NewServer(svc)
+	s := rpc.NewServer(svc)
+	s.OnWrite = api.Touch
+	client := rpc.NewClient(dialServer(t, s))
+
+	// Last-Modified is only sent once the second of the last modification is
+	// over.
+	time.Sleep(time.Second)
+
+	rr := httptest.NewRecorder()
+	api.ServeHTTP(rr, httptest.NewRequest("GET", "/v1/", nil))
+	modified := rr.Header().Get("Last-Modified")
+	if !assert.NotEmpty(t, modified) {
+		return
+	}
+
+	_, err := client.Add("Buy milk", "")
+	assert.NoError(t, err)
+
+	// A REST client must see the Todo added over gRPC.
+	req := httptest.NewRequest("GET", "/v1/", nil)
+	req.Header.Set("If-Modified-Since", modified)
+	rr = httptest.NewRecorder()
+	api.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Buy milk")
+}
+
+func TestRequireToken(t *testing.T) {
+	conn := dialServer(t, rpc.NewServer(&memService{}), grpc.UnaryInterceptor(rpc.RequireToken("secret")))
+	client := gotodopb.NewTodoServiceClient(conn)
+
+	_, err := client.GetAll(context.Background(), &gotodopb.GetAllRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer guess")
+	_, err = client.GetAll(ctx, &gotodopb.GetAllRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	_, err = client.GetAll(ctx, &gotodopb.GetAllRequest{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,193 @@
+// Package rpc serves gotodo.Service over gRPC, and provides a gotodo.Service
+// calling it back.
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/rpc/gotodopb"
+)
+
+// contextBinder is implemented by gotodo.Service decorators which can be bound
+// to the context of a call, e.g. handler.LogService.
+type contextBinder interface {
+	WithContext(ctx context.Context) gotodo.Service
+}
+
+// Server is a gotodopb.TodoServiceServer backed by a gotodo.Service.
+type Server struct {
+	gotodopb.UnimplementedTodoServiceServer
+
+	Service gotodo.Service
+
+	// OnWrite, if set, is called after each call which modifies the Todos,
+	// e.g. to tell handler.Server that its cached responses are stale.
+	OnWrite func()
+}
+
+// NewServer returns a Server backed by svc.
+func NewServer(svc gotodo.Service) *Server {
+	return &Server{Service: svc}
+}
+
+// Register registers s to the gRPC server gs.
+func (s *Server) Register(gs *grpc.Server) {
+	gotodopb.RegisterTodoServiceServer(gs, s)
+}
+
+func (s *Server) written() {
+	if s.OnWrite != nil {
+		s.OnWrite()
+	}
+}
+
+func (s *Server) service(ctx context.Context) gotodo.Service {
+	if binder, ok := s.Service.(contextBinder); ok {
+		return binder.WithContext(ctx)
+	}
+
+	return s.Service
+}
+
+func toProto(todo *gotodo.Todo) *gotodopb.Todo {
+	return &gotodopb.Todo{
+		Id:          int64(todo.ID),
+		Title:       todo.Title,
+		Description: todo.Description,
+		Done:        todo.Done,
+	}
+}
+
+func toProtoList(todos []*gotodo.Todo) *gotodopb.TodoList {
+	list := &gotodopb.TodoList{Todos: make([]*gotodopb.Todo, 0, len(todos))}
+	for _, todo := range todos {
+		list.Todos = append(list.Todos, toProto(todo))
+	}
+
+	return list
+}
+
+// serviceError is the status of an error of the service, once the request is
+// validated: as the service tells no storage failure from another, it is an
+// Internal error.
+func serviceError(err error) error {
+	return status.Error(codes.Internal, err.Error())
+}
+
+// validTitle returns an InvalidArgument error if title is empty.
+func validTitle(title string) error {
+	if title == "" {
+		return status.Error(codes.InvalidArgument, "title must not be empty")
+	}
+
+	return nil
+}
+
+// get returns the Todo with the given ID, or a NotFound error.
+func (s *Server) get(ctx context.Context, id int64) (*gotodo.Todo, error) {
+	todo, err := s.service(ctx).Get(int(id))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "Todo not found")
+	}
+
+	return todo, nil
+}
+
+func (s *Server) Get(ctx context.Context, req *gotodopb.GetRequest) (*gotodopb.Todo, error) {
+	todo, err := s.get(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return toProto(todo), nil
+}
+
+func (s *Server) GetAll(ctx context.Context, req *gotodopb.GetAllRequest) (*gotodopb.TodoList, error) {
+	return toProtoList(s.service(ctx).GetAll()), nil
+}
+
+func (s *Server) GetPending(ctx context.Context, req *gotodopb.GetPendingRequest) (*gotodopb.TodoList, error) {
+	return toProtoList(s.service(ctx).GetPending()), nil
+}
+
+func (s *Server) GetFinished(ctx context.Context, req *gotodopb.GetFinishedRequest) (*gotodopb.TodoList, error) {
+	return toProtoList(s.service(ctx).GetFinished()), nil
+}
+
+func (s *Server) Add(ctx context.Context, req *gotodopb.AddRequest) (*gotodopb.Todo, error) {
+	if err := validTitle(req.GetTitle()); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.service(ctx).Add(req.GetTitle(), req.GetDescription())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	s.written()
+
+	return toProto(todo), nil
+}
+
+// Edit changes the title of a Todo, and its description if the request has
+// one, as the PATCH route of the HTTP API does.
+func (s *Server) Edit(ctx context.Context, req *gotodopb.EditRequest) (*gotodopb.Todo, error) {
+	if err := validTitle(req.GetTitle()); err != nil {
+		return nil, err
+	}
+
+	todo, err := s.get(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	todo.Title = req.GetTitle()
+	if req.Description != nil {
+		todo.Description = req.GetDescription()
+	}
+	if err := s.service(ctx).Edit(todo); err != nil {
+		return nil, serviceError(err)
+	}
+	s.written()
+
+	return toProto(todo), nil
+}
+
+func (s *Server) MarkAsDone(ctx context.Context, req *gotodopb.MarkAsDoneRequest) (*gotodopb.Todo, error) {
+	todo, err := s.get(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service(ctx).MarkAsDone(todo); err != nil {
+		return nil, serviceError(err)
+	}
+	s.written()
+
+	return toProto(todo), nil
+}
+
+func (s *Server) Delete(ctx context.Context, req *gotodopb.DeleteRequest) (*gotodopb.DeleteResponse, error) {
+	todo, err := s.get(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service(ctx).Delete(todo); err != nil {
+		return nil, serviceError(err)
+	}
+	s.written()
+
+	return &gotodopb.DeleteResponse{}, nil
+}
+
+func (s *Server) DeleteFinished(ctx context.Context, req *gotodopb.DeleteFinishedRequest) (*gotodopb.DeleteFinishedResponse, error) {
+	s.service(ctx).DeleteFinished()
+	s.written()
+
+	return &gotodopb.DeleteFinishedResponse{}, nil
+}
@@ -0,0 +1,214 @@
+// Package scan finds the TODO, FIXME and XXX comments of source trees, and
+// keeps track of the Todos created for them.
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Kinds of the comments found.
+var Kinds = []string{"TODO", "FIXME", "XXX"}
+
+// maxFileSize bounds the size of the files read, skipping generated files and
+// data.
+const maxFileSize = 1 << 20
+
+var (
+	slashes = []string{"//", "/*"}
+	hashes  = []string{"#"}
+	dashes  = []string{"--"}
+	markup  = []string{"<!--"}
+	lisp    = []string{";"}
+)
+
+// commentMarkers are the markers starting comments, by file extension.
+var commentMarkers = map[string][]string{
+	".go": slashes, ".c": slashes, ".h": slashes, ".cc": slashes, ".cpp": slashes, ".hpp": slashes,
+	".java": slashes, ".kt": slashes, ".scala": slashes, ".cs": slashes, ".swift": slashes,
+	".js": slashes, ".jsx": slashes, ".ts": slashes, ".tsx": slashes, ".vue": slashes,
+	".rs": slashes, ".php": slashes, ".dart": slashes, ".proto": slashes, ".css": slashes, ".scss": slashes,
+
+	".py": hashes, ".rb": hashes, ".sh": hashes, ".bash": hashes, ".zsh": hashes, ".pl": hashes,
+	".r": hashes, ".yaml": hashes, ".yml": hashes, ".toml": hashes, ".tf": hashes, ".cfg": hashes,
+
+	".sql": dashes, ".lua": dashes, ".hs": dashes,
+
+	".html": markup, ".xml": markup, ".md": markup,
+
+	".el": lisp, ".clj": lisp, ".lisp": lisp, ".ini": lisp,
+}
+
+// commentMarkersByName are the markers starting comments, by file name, for
+// files without extension.
+var commentMarkersByName = map[string][]string{
+	"Makefile":   hashes,
+	"Dockerfile": hashes,
+}
+
+// skippedDirs are not walked, along with hidden directories.
+var skippedDirs = map[string]bool{"vendor": true, "node_modules": true}
+
+// Comment is a TODO, FIXME or XXX comment.
+type Comment struct {
+	// File is the slash-separated path of the file holding the comment,
+	// relative to the scanned directory.
+	File string
+	Line int
+
+	// Kind is either TODO, FIXME or XXX.
+	Kind string
+	Text string
+}
+
+// Location returns where c is, as "file:line".
+func (c Comment) Location() string {
+	return fmt.Sprintf("%s:%d", c.File, c.Line)
+}
+
+// Title returns a title for the Todo of c, e.g. "TODO: close the file".
+func (c Comment) Title() string {
+	if c.Text == "" {
+		return c.Kind + " in " + c.Location()
+	}
+
+	return c.Kind + ": " + c.Text
+}
+
+// markers returns the markers starting comments in the file called name, or
+// nil if the language of the file is unknown.
+func markers(name string) []string {
+	base := path.Base(name)
+	if m, ok := commentMarkersByName[base]; ok {
+		return m
+	}
+
+	return commentMarkers[strings.ToLower(path.Ext(base))]
+}
+
+// Dir returns the comments of the files under root, in the order of their
+// files, then of their lines. Hidden directories and the directories of
+// dependencies are skipped, as are the files of unknown languages.
+func Dir(root string) ([]Comment, error) {
+	var comments []Comment
+
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if p != root && (strings.HasPrefix(info.Name(), ".") || skippedDirs[info.Name()]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || info.Size() > maxFileSize || markers(info.Name()) == nil {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		found, err := File(filepath.ToSlash(rel), f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		comments = append(comments, found...)
+
+		return nil
+	})
+
+	return comments, err
+}
+
+// File returns the comments read from r, the content of the file called
+// name. It returns none if the language of the file is unknown, or if it is
+// binary.
+func File(name string, r io.Reader) ([]Comment, error) {
+	starts := markers(name)
+	if starts == nil {
+		return nil, nil
+	}
+
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(content, 0) >= 0 {
+		return nil, nil
+	}
+
+	var comments []Comment
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, maxFileSize)
+	for n := 1; scanner.Scan(); n++ {
+		if kind, text, ok := parseLine(scanner.Text(), starts); ok {
+			comments = append(comments, Comment{File: name, Line: n, Kind: kind, Text: text})
+		}
+	}
+
+	return comments, scanner.Err()
+}
+
+// parseLine returns the kind and the text of the comment of line if it starts
+// with TODO, FIXME or XXX.
+func parseLine(line string, starts []string) (string, string, bool) {
+	comment, ok := "", false
+	for _, start := range starts {
+		if i := strings.Index(line, start); i >= 0 {
+			comment, ok = line[i+len(start):], true
+			break
+		}
+	}
+	if !ok && starts[len(starts)-1] == "/*" && strings.HasPrefix(strings.TrimSpace(line), "*") {
+		// A line inside a block comment.
+		comment, ok = strings.TrimSpace(line), true
+	}
+	if !ok {
+		return "", "", false
+	}
+
+	comment = strings.TrimLeft(comment, " \t*/#;-!")
+	for _, kind := range Kinds {
+		if !strings.HasPrefix(comment, kind) {
+			continue
+		}
+
+		text := comment[len(kind):]
+		if text != "" && (unicode.IsLetter(rune(text[0])) || unicode.IsDigit(rune(text[0]))) {
+			// A word starting with the kind, e.g. "TODOs".
+			return "", "", false
+		}
+
+		// Skip an author, e.g. "TODO(alice):".
+		if strings.HasPrefix(text, "(") {
+			if end := strings.Index(text, ")"); end >= 0 {
+				text = text[end+1:]
+			}
+		}
+		text = strings.TrimSuffix(strings.TrimSpace(text), "*/")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "-->")
+		text = strings.TrimSpace(strings.TrimLeft(text, " \t:-"))
+
+		return kind, text, true
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,121 @@
+package scan
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFile(t *testing.T) {
+	for _, tc := range []struct {
+		name, content string
+		comments      []Comment
+	}{
+		{"main.go", "package main\n\n// TODO: close the file\nfunc main() {} // FIXME(alice) handle errors\n", []Comment{
+			{File: "main.go", Line: 3, Kind: "TODO", Text: "close the file"},
+			{File: "main.go", Line: 4, Kind: "FIXME", Text: "handle errors"},
+		}},
+		{"lib.c", "/* XXX: not thread safe */\n/*\n * TODO - free the buffer\n */\n", []Comment{
+			{File: "lib.c", Line: 1, Kind: "XXX", Text: "not thread safe"},
+			{File: "lib.c", Line: 3, Kind: "TODO", Text: "free the buffer"},
+		}},
+		{"app.py", "# TODO\nx = 1  # TODOs are fine\n", []Comment{
+			{File: "app.py", Line: 1, Kind: "TODO"},
+		}},
+		{"schema.sql", "-- FIXME: add an index\n", []Comment{
+			{File: "schema.sql", Line: 1, Kind: "FIXME", Text: "add an index"},
+		}},
+		{"README.md", "<!-- TODO: document scan -->\n", []Comment{
+			{File: "README.md", Line: 1, Kind: "TODO", Text: "document scan"},
+		}},
+		{"Makefile", "# XXX: use go vet\n", []Comment{
+			{File: "Makefile", Line: 1, Kind: "XXX", Text: "use go vet"},
+		}},
+		{"main.go", `fmt.Println("TODO: not a comment")`, nil},
+		{"notes.txt", "TODO: unknown language\n", nil},
+		{"image.go", "// TODO\x00", nil},
+	} {
+		comments, err := File(tc.name, strings.NewReader(tc.content))
+
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.comments, comments, tc.name)
+	}
+}
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"main.go":              "// TODO: a\n",
+		"sub/lib.py":           "# FIXME: b\n",
+		"vendor/dep/dep.go":    "// TODO: vendored\n",
+		".git/hooks/pre.sh":    "# TODO: hidden\n",
+		"node_modules/x/x.js":  "// TODO: dependency\n",
+		"sub/deeper/README.md": "no comment\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	comments, err := Dir(dir)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Comment{
+		{File: "main.go", Line: 1, Kind: "TODO", Text: "a"},
+		{File: "sub/lib.py", Line: 1, Kind: "FIXME", Text: "b"},
+	}, comments)
+}
+
+func TestDiff(t *testing.T) {
+	s := &State{}
+	s.Track(1, Comment{File: "a.go", Line: 3, Kind: "TODO", Text: "same"})
+	s.Track(2, Comment{File: "a.go", Line: 5, Kind: "TODO", Text: "same"})
+	s.Track(3, Comment{File: "a.go", Line: 9, Kind: "FIXME", Text: "reworded"})
+	s.Track(4, Comment{File: "b.go", Line: 1, Kind: "XXX", Text: "kept"})
+
+	changes := s.Diff([]Comment{
+		{File: "a.go", Line: 4, Kind: "TODO", Text: "same"},
+		{File: "a.go", Line: 6, Kind: "TODO", Text: "same"},
+		{File: "a.go", Line: 7, Kind: "TODO", Text: "same"},
+		{File: "a.go", Line: 9, Kind: "FIXME", Text: "reworded again"},
+		{File: "b.go", Line: 1, Kind: "XXX", Text: "kept"},
+	})
+
+	assert.Equal(t, []Comment{
+		{File: "a.go", Line: 7, Kind: "TODO", Text: "same"},
+		{File: "a.go", Line: 9, Kind: "FIXME", Text: "reworded again"},
+	}, changes.New)
+	if assert.Len(t, changes.Moved, 2) {
+		assert.Equal(t, 1, changes.Moved[0].Tracked.ID)
+		assert.Equal(t, 4, changes.Moved[0].To.Line)
+		assert.Equal(t, 2, changes.Moved[1].Tracked.ID)
+		assert.Equal(t, 6, changes.Moved[1].To.Line)
+	}
+	if assert.Len(t, changes.Gone, 1) {
+		assert.Equal(t, 3, changes.Gone[0].ID)
+	}
+}
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StateFile)
+
+	s, err := LoadState(path)
+	assert.NoError(t, err)
+	assert.Empty(t, s.Todos)
+
+	s.Track(2, Comment{File: "b.go", Line: 1, Kind: "TODO", Text: "b"})
+	s.Track(1, Comment{File: "a.go", Line: 1, Kind: "TODO", Text: "a"})
+	assert.NoError(t, s.Save())
+
+	s, err = LoadState(path)
+	assert.NoError(t, err)
+	if assert.Len(t, s.Todos, 2) {
+		assert.Equal(t, "a.go", s.Todos[0].File)
+		s.Untrack(s.Todos[0])
+		assert.Equal(t, 2, s.Todos[0].ID)
+	}
+}
@@ -0,0 +1,172 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// StateFile is the name of the state file kept at the root of the scanned
+// directories.
+const StateFile = ".gotodo-scan.json"
+
+// Tracked is a comment a Todo was created for.
+type Tracked struct {
+	ID int `json:"id"`
+
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+// Comment returns the comment t tracks.
+func (t *Tracked) Comment() Comment {
+	return Comment{File: t.File, Line: t.Line, Kind: t.Kind, Text: t.Text}
+}
+
+// State tells which Todo was created for which comment in a scanned
+// directory.
+type State struct {
+	// Path is where the state is read from and saved to.
+	Path string `json:"-"`
+
+	Todos []*Tracked `json:"todos"`
+}
+
+// LoadState reads the state file at path. A missing file tracks no comment.
+func LoadState(path string) (*State, error) {
+	s := &State{Path: path}
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Save writes s to its path, with the comments sorted by location.
+func (s *State) Save() error {
+	sort.SliceStable(s.Todos, func(i, j int) bool {
+		if s.Todos[i].File != s.Todos[j].File {
+			return s.Todos[i].File < s.Todos[j].File
+		}
+		return s.Todos[i].Line < s.Todos[j].Line
+	})
+
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(s.Path, append(content, '\n'), 0644)
+}
+
+// Track records that the Todo identified by id was created for c.
+func (s *State) Track(id int, c Comment) {
+	s.Todos = append(s.Todos, &Tracked{ID: id, File: c.File, Line: c.Line, Kind: c.Kind, Text: c.Text})
+}
+
+// Untrack forgets t.
+func (s *State) Untrack(t *Tracked) {
+	for i, tracked := range s.Todos {
+		if tracked == t {
+			s.Todos = append(s.Todos[:i], s.Todos[i+1:]...)
+			return
+		}
+	}
+}
+
+// Move is a tracked comment found at another line.
+type Move struct {
+	Tracked *Tracked
+	To      Comment
+}
+
+// Changes are the differences between the tracked comments and those found
+// by a scan.
+type Changes struct {
+	// New are the comments found which are not tracked.
+	New []Comment
+	// Moved are the tracked comments found at another line.
+	Moved []Move
+	// Gone are the tracked comments which were not found.
+	Gone []*Tracked
+}
+
+// Empty tells whether there is no change.
+func (c Changes) Empty() bool {
+	return len(c.New) == 0 && len(c.Moved) == 0 && len(c.Gone) == 0
+}
+
+// Diff compares the comments tracked by s with those found. Comments are told
+// apart by their file, kind and text, so that a comment is still tracked once
+// lines are added above it, while editing its text makes it a new one. Among
+// identical comments of a file, the first tracked one is the first found, and
+// so on.
+func (s *State) Diff(found []Comment) Changes {
+	type key struct {
+		file, kind, text string
+		n                int
+	}
+	keyOf := func(c Comment, seen map[key]int) key {
+		k := key{file: c.File, kind: c.Kind, text: c.Text}
+		k.n = seen[k]
+		seen[k]++
+
+		return k
+	}
+
+	tracked := make([]*Tracked, len(s.Todos))
+	copy(tracked, s.Todos)
+	sort.SliceStable(tracked, func(i, j int) bool {
+		if tracked[i].File != tracked[j].File {
+			return tracked[i].File < tracked[j].File
+		}
+		return tracked[i].Line < tracked[j].Line
+	})
+
+	byKey := map[key]*Tracked{}
+	seen := map[key]int{}
+	for _, t := range tracked {
+		byKey[keyOf(t.Comment(), seen)] = t
+	}
+
+	var changes Changes
+	seen = map[key]int{}
+	for _, c := range found {
+		k := keyOf(c, seen)
+		t, ok := byKey[k]
+		if !ok {
+			changes.New = append(changes.New, c)
+			continue
+		}
+
+		delete(byKey, k)
+		if t.Line != c.Line {
+			changes.Moved = append(changes.Moved, Move{Tracked: t, To: c})
+		}
+	}
+
+	left := map[*Tracked]bool{}
+	for _, t := range byKey {
+		left[t] = true
+	}
+	for _, t := range tracked {
+		if left[t] {
+			changes.Gone = append(changes.Gone, t)
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,294 @@
+// Package server runs a http.Handler with timeouts, and shuts it down
+// gracefully on SIGINT or SIGTERM.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Config holds the settings of a Runner.
+type Config struct {
+	// Addr is the TCP address to listen on, e.g. ":8080".
+	Addr string
+
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+
+	// ShutdownTimeout is the time given to in-flight requests to finish once
+	// shutdown is requested.
+	ShutdownTimeout time.Duration
+
+	// TLSCertFile and TLSKeyFile enable HTTPS when both are set. The files are
+	// checked for changes every TLSReloadInterval.
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSReloadInterval time.Duration
+
+	// TLSClientCAFile, if set, requires clients to present a certificate
+	// signed by one of the CAs in this PEM bundle.
+	TLSClientCAFile string
+
+	// RedirectAddr, if set along with TLS, is the TCP address on which plain
+	// HTTP requests are redirected to HTTPS.
+	RedirectAddr string
+}
+
+// TLS reports whether c enables HTTPS.
+func (c Config) TLS() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// DefaultConfig returns the Config used for settings which are not set.
+func DefaultConfig() Config {
+	return Config{
+		Addr:              ":8080",
+		ReadTimeout:       15 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		MaxHeaderBytes:    1 << 20,
+		ShutdownTimeout:   20 * time.Second,
+		TLSReloadInterval: time.Minute,
+	}
+}
+
+// Runner serves Handler until it is asked to stop.
+type Runner struct {
+	Config  Config
+	Handler http.Handler
+	Logger  *slog.Logger
+
+	// Listener is used instead of listening on Config.Addr, if set.
+	Listener net.Listener
+
+	// Sidecars are served along with Handler, each on its own address, and are
+	// shut down along with it.
+	Sidecars []Sidecar
+
+	// Closers are closed once the server is shut down, e.g. the storage
+	// repository behind Handler.
+	Closers []io.Closer
+}
+
+// Backend is a server which is not an HTTP server, e.g. a *grpc.Server.
+type Backend interface {
+	Serve(listener net.Listener) error
+	GracefulStop()
+	Stop()
+}
+
+// Sidecar is a Backend run by a Runner. It is served with the TLS settings of
+// the Runner, if any.
+type Sidecar struct {
+	Name    string
+	Addr    string
+	Backend Backend
+
+	// Listener is used instead of listening on Addr, if set.
+	Listener net.Listener
+}
+
+// Run serves Handler until ctx is done or the process receives SIGINT or
+// SIGTERM. It then stops accepting connections and waits for in-flight
+// requests to finish within Config.ShutdownTimeout, before closing Closers.
+func (r *Runner) Run(ctx context.Context) error {
+	logger := r.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	srv := &http.Server{
+		Addr:              r.Config.Addr,
+		Handler:           r.Handler,
+		ReadTimeout:       r.Config.ReadTimeout,
+		ReadHeaderTimeout: r.Config.ReadHeaderTimeout,
+		WriteTimeout:      r.Config.WriteTimeout,
+		IdleTimeout:       r.Config.IdleTimeout,
+		MaxHeaderBytes:    r.Config.MaxHeaderBytes,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	servers := []*http.Server{srv}
+	if r.Config.TLS() {
+		tlsConfig, err := r.tlsConfig(ctx, logger)
+		if err != nil {
+			return err
+		}
+		srv.TLSConfig = tlsConfig
+
+		if r.Config.RedirectAddr != "" {
+			servers = append(servers, &http.Server{
+				Addr:              r.Config.RedirectAddr,
+				Handler:           RedirectToHTTPS(r.Config.Addr),
+				ReadTimeout:       r.Config.ReadTimeout,
+				ReadHeaderTimeout: r.Config.ReadHeaderTimeout,
+				WriteTimeout:      r.Config.WriteTimeout,
+				IdleTimeout:       r.Config.IdleTimeout,
+				MaxHeaderBytes:    r.Config.MaxHeaderBytes,
+			})
+		}
+	}
+
+	listeners := make([]net.Listener, len(r.Sidecars))
+	for i, sc := range r.Sidecars {
+		listener := sc.Listener
+		if listener == nil {
+			var err error
+			if listener, err = net.Listen("tcp", sc.Addr); err != nil {
+				for _, l := range listeners[:i] {
+					l.Close()
+				}
+				return err
+			}
+		}
+
+		if srv.TLSConfig != nil {
+			tlsConfig := srv.TLSConfig.Clone()
+			tlsConfig.NextProtos = []string{"h2"}
+			listener = tls.NewListener(listener, tlsConfig)
+		}
+		listeners[i] = listener
+	}
+
+	serveErr := make(chan error, len(servers)+len(r.Sidecars))
+	for i, sc := range r.Sidecars {
+		logger.Info("listening", "server", sc.Name, "addr", listeners[i].Addr().String(), "tls", srv.TLSConfig != nil)
+
+		go func(b Backend, listener net.Listener) {
+			serveErr <- b.Serve(listener)
+		}(sc.Backend, listeners[i])
+	}
+
+	for i, s := range servers {
+		// Only the main server may be given a listener.
+		var listener net.Listener
+		if i == 0 {
+			listener = r.Listener
+		}
+
+		go func(s *http.Server) {
+			serveErr <- serve(s, listener, logger)
+		}(s)
+	}
+
+	var err error
+	select {
+	case err = <-serveErr:
+		// A server failed before shutdown was requested.
+		for _, s := range servers {
+			s.Close()
+		}
+		for _, sc := range r.Sidecars {
+			sc.Backend.Stop()
+		}
+	case <-ctx.Done():
+		logger.Info("shutting down", "timeout", r.Config.ShutdownTimeout.String())
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), r.Config.ShutdownTimeout)
+		for _, s := range servers {
+			if serr := s.Shutdown(shutdownCtx); serr != nil {
+				logger.Error("cannot drain connections", "addr", s.Addr, "error", serr)
+				s.Close()
+				err = serr
+			}
+		}
+		for _, sc := range r.Sidecars {
+			if serr := stopGracefully(shutdownCtx, sc.Backend); serr != nil {
+				logger.Error("cannot drain connections", "server", sc.Name, "error", serr)
+				err = serr
+			}
+		}
+		cancel()
+	}
+
+	if err == http.ErrServerClosed {
+		err = nil
+	}
+
+	for _, c := range r.Closers {
+		if cerr := c.Close(); cerr != nil {
+			logger.Error("cannot close", "error", cerr)
+			if err == nil {
+				err = cerr
+			}
+		}
+	}
+
+	return err
+}
+
+// stopGracefully stops b once it finishes serving in-flight calls, or at once
+// when ctx is done.
+func stopGracefully(ctx context.Context, b Backend) error {
+	stopped := make(chan struct{})
+	go func() {
+		b.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		b.Stop()
+		return ctx.Err()
+	}
+}
+
+func serve(s *http.Server, listener net.Listener, logger *slog.Logger) error {
+	if listener != nil {
+		logger.Info("listening", "addr", listener.Addr().String(), "tls", s.TLSConfig != nil)
+		if s.TLSConfig != nil {
+			return s.ServeTLS(listener, "", "")
+		}
+		return s.Serve(listener)
+	}
+
+	logger.Info("listening", "addr", s.Addr, "tls", s.TLSConfig != nil)
+	if s.TLSConfig != nil {
+		return s.ListenAndServeTLS("", "")
+	}
+	return s.ListenAndServe()
+}
+
+func (r *Runner) tlsConfig(ctx context.Context, logger *slog.Logger) (*tls.Config, error) {
+	reloader, err := NewCertReloader(r.Config.TLSCertFile, r.Config.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Config.TLSReloadInterval > 0 {
+		go reloader.Watch(ctx, r.Config.TLSReloadInterval, logger)
+	}
+
+	config := &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+
+	if r.Config.TLSClientCAFile != "" {
+		pool, err := loadCertPool(r.Config.TLSClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
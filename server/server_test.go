@@ -0,0 +1,124 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodoapp/server"
+)
+
+type closer struct {
+	closed bool
+}
+
+func (c *closer) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestRunDrainsRequests(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+	storage := &closer{}
+	runner := &server.Runner{
+		Config: server.DefaultConfig(),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+			w.Write([]byte("done"))
+		}),
+		Logger:   slog.New(slog.NewTextHandler(ioutil.Discard, nil)),
+		Listener: listener,
+		Closers:  []io.Closer{storage},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- runner.Run(ctx)
+	}()
+
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+
+		body, _ := ioutil.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+
+	<-started
+	cancel()
+
+	assert.Equal(t, "done", <-response)
+	assert.NoError(t, <-stopped)
+	assert.True(t, storage.closed)
+}
+
+// backend is a server.Backend which records how it is served and stopped.
+type backend struct {
+	served   chan net.Listener
+	graceful bool
+	stop     chan struct{}
+}
+
+func (b *backend) Serve(listener net.Listener) error {
+	b.served <- listener
+	<-b.stop
+	return nil
+}
+
+func (b *backend) GracefulStop() {
+	b.graceful = true
+	close(b.stop)
+}
+
+func (b *backend) Stop() {}
+
+func TestRunSidecars(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sidecarListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := &backend{served: make(chan net.Listener, 1), stop: make(chan struct{})}
+	runner := &server.Runner{
+		Config:   server.DefaultConfig(),
+		Handler:  http.NotFoundHandler(),
+		Logger:   slog.New(slog.NewTextHandler(ioutil.Discard, nil)),
+		Listener: listener,
+		Sidecars: []server.Sidecar{{Name: "test", Backend: b, Listener: sidecarListener}},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- runner.Run(ctx)
+	}()
+
+	assert.Equal(t, sidecarListener, <-b.served)
+	cancel()
+
+	assert.NoError(t, <-stopped)
+	assert.True(t, b.graceful)
+}
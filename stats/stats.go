@@ -0,0 +1,260 @@
+// Package stats reports how many Todos are pending and finished, and how many
+// were created and completed over time.
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/timestamps"
+)
+
+// Intervals of a Report.
+const (
+	Day  = "day"
+	Week = "week"
+)
+
+// Defaults of Options.
+const (
+	DefaultDays   = 14
+	DefaultWeeks  = 8
+	DefaultOldest = 5
+
+	// maxIntervals bounds Options.Last.
+	maxIntervals = 366
+)
+
+// Options tell what a Report covers.
+type Options struct {
+	// By is the interval the Todos created and completed are counted by,
+	// either Day or Week. It defaults to Day.
+	By string
+	// Last is the number of intervals counted, up to the current one. It
+	// defaults to DefaultDays or DefaultWeeks.
+	Last int
+	// Oldest is the number of oldest pending Todos reported. It defaults to
+	// DefaultOldest.
+	Oldest int
+
+	// Now is the time the Report is made at, in the time zone the intervals
+	// start in. It defaults to the current time.
+	Now time.Time
+}
+
+// Validate checks that the options of o are valid.
+func (o Options) Validate() error {
+	_, err := o.withDefaults()
+	return err
+}
+
+// withDefaults returns o with its unset options set to their default, or an
+// error if an option is invalid.
+func (o Options) withDefaults() (Options, error) {
+	switch o.By {
+	case "":
+		o.By = Day
+	case Day, Week:
+	default:
+		return o, fmt.Errorf("interval %q must be either %s or %s", o.By, Day, Week)
+	}
+
+	switch {
+	case o.Last < 0 || o.Last > maxIntervals:
+		return o, fmt.Errorf("number of intervals must be between 1 and %d", maxIntervals)
+	case o.Last == 0 && o.By == Week:
+		o.Last = DefaultWeeks
+	case o.Last == 0:
+		o.Last = DefaultDays
+	}
+
+	if o.Oldest < 0 {
+		return o, fmt.Errorf("number of oldest todos must not be negative")
+	}
+	if o.Oldest == 0 {
+		o.Oldest = DefaultOldest
+	}
+
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+
+	return o, nil
+}
+
+// Report holds the statistics of the Todos.
+type Report struct {
+	Pending  int `json:"pending"`
+	Finished int `json:"finished"`
+	// CompletionRate is the share of the Todos which are finished, from 0 to 1.
+	CompletionRate float64 `json:"completion_rate"`
+
+	// By is the interval History is counted by.
+	By string `json:"by"`
+	// History counts the Todos created and completed in each interval, the
+	// current one last. It is empty if the Times of the Todos are unknown.
+	History []Interval `json:"history"`
+
+	// Oldest are the oldest pending Todos, the oldest first.
+	Oldest []Open `json:"oldest_pending"`
+}
+
+// Interval counts the Todos created and completed from Start, during a day or
+// a week.
+type Interval struct {
+	Start     time.Time `json:"start"`
+	Created   int       `json:"created"`
+	Completed int       `json:"completed"`
+}
+
+// Open is a pending Todo.
+type Open struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	// CreatedAt is nil if the creation time of the Todo is unknown.
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// Reporter is implemented by the Services which make their own Reports, e.g.
+// the client of a gotodoserver.
+type Reporter interface {
+	Stats(opts Options) (*Report, error)
+}
+
+// start returns the start of the interval t is in: midnight for a day, and
+// midnight on Monday for a week.
+func start(t time.Time, by string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if by == Week {
+		day = day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+
+	return day
+}
+
+// Compute reports on the Todos of svc. times are their Times, or nil if they
+// are unknown.
+func Compute(svc gotodo.Service, times map[int]timestamps.Times, opts Options) (*Report, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := svc.GetPending()
+	r := &Report{
+		Pending:  len(pending),
+		Finished: len(svc.GetFinished()),
+		By:       opts.By,
+		History:  []Interval{},
+		Oldest:   []Open{},
+	}
+	if total := r.Pending + r.Finished; total > 0 {
+		r.CompletionRate = float64(r.Finished) / float64(total)
+	}
+
+	if times != nil {
+		days := 1
+		if opts.By == Week {
+			days = 7
+		}
+
+		current := start(opts.Now, opts.By)
+		for i := opts.Last - 1; i >= 0; i-- {
+			r.History = append(r.History, Interval{Start: current.AddDate(0, 0, -i*days)})
+		}
+
+		// index returns the index of the interval t is in, or -1.
+		index := func(t time.Time) int {
+			if t.IsZero() {
+				return -1
+			}
+			for i := len(r.History) - 1; i >= 0; i-- {
+				if !t.Before(r.History[i].Start) {
+					if i == len(r.History)-1 && t.After(opts.Now) {
+						return -1
+					}
+					return i
+				}
+			}
+			return -1
+		}
+
+		for _, t := range times {
+			if i := index(t.CreatedAt); i >= 0 {
+				r.History[i].Created++
+			}
+			if i := index(t.CompletedAt); i >= 0 {
+				r.History[i].Completed++
+			}
+		}
+	}
+
+	// Todos of unknown creation time predate those of known one, as they were
+	// created before Times were recorded.
+	sort.SliceStable(pending, func(i, j int) bool {
+		a, b := times[pending[i].ID].CreatedAt, times[pending[j].ID].CreatedAt
+		if a.IsZero() != b.IsZero() {
+			return a.IsZero()
+		}
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return pending[i].ID < pending[j].ID
+	})
+	for _, todo := range pending {
+		if len(r.Oldest) == opts.Oldest {
+			break
+		}
+
+		open := Open{ID: todo.ID, Title: todo.Title}
+		if created := times[todo.ID].CreatedAt; !created.IsZero() {
+			open.CreatedAt = &created
+		}
+		r.Oldest = append(r.Oldest, open)
+	}
+
+	return r, nil
+}
+
+// sparkLevels are the ASCII characters of a sparkline, from the lowest to the
+// highest value.
+const sparkLevels = "_.-=+*#%@"
+
+// Sparkline draws values as ASCII characters, scaled to the highest value.
+func Sparkline(values []int) string {
+	highest := 0
+	for _, v := range values {
+		if v > highest {
+			highest = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if highest > 0 && v > 0 {
+			level = 1 + v*(len(sparkLevels)-2)/highest
+		}
+		b.WriteByte(sparkLevels[level])
+	}
+
+	return b.String()
+}
+
+// Bar draws value as a bar of '#', scaled so that highest is width
+// characters long.
+func Bar(value, highest, width int) string {
+	if highest <= 0 || value <= 0 {
+		return ""
+	}
+
+	n := value * width / highest
+	if n == 0 {
+		n = 1
+	}
+
+	return strings.Repeat("#", n)
+}
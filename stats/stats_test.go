@@ -0,0 +1,97 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodo"
+	"github.com/saifulwebid/gotodoapp/timestamps"
+)
+
+// todoList is the part of a gotodo.Service which Compute reads.
+type todoList struct {
+	gotodo.Service
+	todos []*gotodo.Todo
+}
+
+func (l *todoList) filter(done bool) []*gotodo.Todo {
+	var todos []*gotodo.Todo
+	for _, todo := range l.todos {
+		if todo.Done == done {
+			todos = append(todos, todo)
+		}
+	}
+
+	return todos
+}
+
+func (l *todoList) GetPending() []*gotodo.Todo  { return l.filter(false) }
+func (l *todoList) GetFinished() []*gotodo.Todo { return l.filter(true) }
+
+func TestCompute(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2026, 10, 21, 15, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	svc := &todoList{todos: []*gotodo.Todo{
+		{ID: 1, Title: "Predates timestamps"},
+		{ID: 2, Title: "Buy milk", Done: true},
+		{ID: 3, Title: "Buy eggs"},
+		{ID: 4, Title: "Walk the dog"},
+	}}
+	times := map[int]timestamps.Times{
+		2: {CreatedAt: day(9), CompletedAt: day(1)},
+		3: {CreatedAt: day(2)},
+		4: {CreatedAt: day(5)},
+		// Deleted.
+		5: {CreatedAt: day(1), CompletedAt: day(0), DeletedAt: day(0)},
+	}
+
+	r, err := Compute(svc, times, Options{Last: 3, Oldest: 3, Now: now})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, r.Pending)
+	assert.Equal(t, 1, r.Finished)
+	assert.Equal(t, 0.25, r.CompletionRate)
+	assert.Equal(t, Day, r.By)
+	assert.Equal(t, []Interval{
+		{Start: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), Created: 1},
+		{Start: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), Created: 1, Completed: 1},
+		{Start: time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC), Completed: 1},
+	}, r.History)
+	if assert.Len(t, r.Oldest, 3) {
+		assert.Equal(t, 1, r.Oldest[0].ID)
+		assert.Nil(t, r.Oldest[0].CreatedAt)
+		assert.Equal(t, 4, r.Oldest[1].ID)
+		assert.Equal(t, 3, r.Oldest[2].ID)
+	}
+
+	r, err = Compute(svc, times, Options{By: Week, Now: now})
+	assert.NoError(t, err)
+	if assert.Len(t, r.History, DefaultWeeks) {
+		assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), r.History[DefaultWeeks-1].Start)
+		assert.Equal(t, Interval{Start: time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), Created: 2}, r.History[DefaultWeeks-2])
+		assert.Equal(t, 2, r.History[DefaultWeeks-1].Created)
+		assert.Equal(t, 2, r.History[DefaultWeeks-1].Completed)
+	}
+
+	r, err = Compute(svc, nil, Options{Now: now})
+	assert.NoError(t, err)
+	assert.Empty(t, r.History)
+	assert.Len(t, r.Oldest, 3)
+
+	_, err = Compute(svc, nil, Options{By: "month"})
+	assert.EqualError(t, err, `interval "month" must be either day or week`)
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "_.+@", Sparkline([]int{0, 1, 4, 8}))
+	assert.Equal(t, "___", Sparkline([]int{0, 0, 0}))
+}
+
+func TestBar(t *testing.T) {
+	assert.Equal(t, "##########", Bar(5, 10, 20))
+	assert.Equal(t, "#", Bar(1, 100, 20))
+	assert.Equal(t, "", Bar(0, 10, 20))
+}
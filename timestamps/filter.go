@@ -0,0 +1,127 @@
+package timestamps
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+// Fields of Times a Filter applies to.
+const (
+	FieldCreated   = "created"
+	FieldUpdated   = "updated"
+	FieldCompleted = "completed"
+)
+
+// Filter selects the Todos whose timestamp in Field is from Since, included,
+// until Until, excluded. Unset bounds are open.
+type Filter struct {
+	// Field is either FieldCreated, FieldUpdated or FieldCompleted.
+	Field string
+	Since time.Time
+	Until time.Time
+}
+
+// ParseFilter returns the Filter of since and until, which are either RFC
+// 3339 times, dates, or durations back from now such as "36h" or "7d". A date
+// is the start of that day as since, and the end of it as until. field
+// defaults to FieldCreated.
+func ParseFilter(field, since, until string, now time.Time) (Filter, error) {
+	f := Filter{Field: field}
+
+	switch f.Field {
+	case "":
+		f.Field = FieldCreated
+	case FieldCreated, FieldUpdated, FieldCompleted:
+	default:
+		return f, fmt.Errorf("timestamp %q must be either %s, %s or %s", field, FieldCreated, FieldUpdated, FieldCompleted)
+	}
+
+	var err error
+	if since != "" {
+		if f.Since, err = parseTime(since, now, false); err != nil {
+			return f, fmt.Errorf("since: %w", err)
+		}
+	}
+	if until != "" {
+		if f.Until, err = parseTime(until, now, true); err != nil {
+			return f, fmt.Errorf("until: %w", err)
+		}
+	}
+
+	return f, nil
+}
+
+// parseTime parses s as an RFC 3339 time, a date in the time zone of now,
+// or a duration back from now. A date is the start of that day, or the start
+// of the next day if end is set.
+func parseTime(s string, now time.Time, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		if end {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+
+	// time.ParseDuration knows no days or weeks.
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	d, err := time.ParseDuration(s)
+	if unit, ok := units[s[len(s)-1:]]; ok && err != nil {
+		var n int
+		n, err = strconv.Atoi(strings.TrimSuffix(s, s[len(s)-1:]))
+		d = time.Duration(n) * unit
+	}
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("%q must be a time such as 2006-01-02T15:04:05Z, a date such as 2006-01-02, or a duration such as 36h or 7d", s)
+	}
+
+	return now.Add(-d), nil
+}
+
+// IsZero tells whether f selects every Todo.
+func (f Filter) IsZero() bool {
+	return f.Since.IsZero() && f.Until.IsZero()
+}
+
+// Match tells whether f selects the Todo of times. A Todo whose timestamp in
+// f.Field is unknown is only selected if f is zero.
+func (f Filter) Match(times Times) bool {
+	if f.IsZero() {
+		return true
+	}
+
+	t := times.CreatedAt
+	switch f.Field {
+	case FieldUpdated:
+		t = times.UpdatedAt
+	case FieldCompleted:
+		t = times.CompletedAt
+	}
+
+	return !t.IsZero() &&
+		(f.Since.IsZero() || !t.Before(f.Since)) &&
+		(f.Until.IsZero() || t.Before(f.Until))
+}
+
+// Apply returns the todos selected by f, of which times are the Times.
+func (f Filter) Apply(todos []*gotodo.Todo, times map[int]Times) []*gotodo.Todo {
+	if f.IsZero() {
+		return todos
+	}
+
+	selected := []*gotodo.Todo{}
+	for _, todo := range todos {
+		if f.Match(times[todo.ID]) {
+			selected = append(selected, todo)
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,107 @@
+package timestamps
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// createTable creates the table of the Times, next to the tables of gotodo.
+const createTable = `CREATE TABLE IF NOT EXISTS todo_times (
+	todo_id INT UNSIGNED NOT NULL PRIMARY KEY,
+	created_at DATETIME(6) NULL,
+	updated_at DATETIME(6) NULL,
+	completed_at DATETIME(6) NULL,
+	deleted_at DATETIME(6) NULL
+)`
+
+// SQLStore is a Store which keeps Times in the todo_times table of a MySQL
+// database.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// MySQLDSN returns the data source name of a MySQL database.
+func MySQLDSN(host string, port int, name, user, pass string) string {
+	cfg := mysql.NewConfig()
+	cfg.Net = "tcp"
+	cfg.Addr = fmt.Sprintf("%s:%d", host, port)
+	cfg.DBName = name
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.ParseTime = true
+
+	return cfg.FormatDSN()
+}
+
+// OpenSQL connects to the MySQL database at dsn, creating the todo_times
+// table if it does not exist.
+func OpenSQL(dsn string) (*SQLStore, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(createTable); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot create the todo_times table: %w", err)
+	}
+
+	return &SQLStore{db: db}, nil
+}
+
+// Close closes the connection to the database.
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLStore) Get(ids ...int) (map[int]Times, error) {
+	query := "SELECT todo_id, created_at, updated_at, completed_at, deleted_at FROM todo_times"
+	args := make([]interface{}, len(ids))
+	if len(ids) > 0 {
+		query += " WHERE todo_id IN (?" + strings.Repeat(", ?", len(ids)-1) + ")"
+		for i, id := range ids {
+			args[i] = id
+		}
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := map[int]Times{}
+	for rows.Next() {
+		var (
+			id                                   int
+			created, updated, completed, deleted sql.NullTime
+		)
+		if err := rows.Scan(&id, &created, &updated, &completed, &deleted); err != nil {
+			return nil, err
+		}
+
+		found[id] = Times{CreatedAt: created.Time, UpdatedAt: updated.Time, CompletedAt: completed.Time, DeletedAt: deleted.Time}
+	}
+
+	return found, rows.Err()
+}
+
+func (s *SQLStore) Put(id int, times Times) error {
+	_, err := s.db.Exec("REPLACE INTO todo_times (todo_id, created_at, updated_at, completed_at, deleted_at) VALUES (?, ?, ?, ?, ?)",
+		id, nullTime(times.CreatedAt), nullTime(times.UpdatedAt), nullTime(times.CompletedAt), nullTime(times.DeletedAt))
+
+	return err
+}
+
+// nullTime returns t in UTC, or NULL if it is zero.
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
@@ -0,0 +1,233 @@
+// Package timestamps records when Todos are created, updated, completed and
+// deleted, as gotodo.Todo carries no timestamp. Service decorates a
+// gotodo.Service to record them in a Store.
+package timestamps
+
+import (
+	"sync"
+	"time"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+// Times are the timestamps of a Todo. Those which are unknown, e.g. the
+// creation time of a Todo created before timestamps were recorded, are zero.
+type Times struct {
+	CreatedAt time.Time
+	// UpdatedAt is when the Todo was last created, edited or marked as done.
+	UpdatedAt   time.Time
+	CompletedAt time.Time
+	DeletedAt   time.Time
+}
+
+// Todo is a gotodo.Todo along with its Times, as encoded in JSON. Unknown
+// Times are left out.
+type Todo struct {
+	*gotodo.Todo
+
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// WithTimes returns todo along with its times.
+func WithTimes(todo *gotodo.Todo, times Times) *Todo {
+	known := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		return &t
+	}
+
+	return &Todo{
+		Todo:        todo,
+		CreatedAt:   known(times.CreatedAt),
+		UpdatedAt:   known(times.UpdatedAt),
+		CompletedAt: known(times.CompletedAt),
+	}
+}
+
+// Times returns the Times of t.
+func (t *Todo) Times() Times {
+	var times Times
+	if t.CreatedAt != nil {
+		times.CreatedAt = *t.CreatedAt
+	}
+	if t.UpdatedAt != nil {
+		times.UpdatedAt = *t.UpdatedAt
+	}
+	if t.CompletedAt != nil {
+		times.CompletedAt = *t.CompletedAt
+	}
+
+	return times
+}
+
+// Store keeps the Times of Todos.
+type Store interface {
+	// Get returns the Times of the Todos identified by ids, or of every Todo
+	// if ids is empty, by ID. Todos without Times are missing.
+	Get(ids ...int) (map[int]Times, error)
+	// Put sets the Times of the Todo identified by id.
+	Put(id int, times Times) error
+}
+
+// Timed is implemented by the Services which know the Times of their Todos.
+type Timed interface {
+	Times(ids ...int) (map[int]Times, error)
+}
+
+// MemoryStore is a Store which keeps Times in memory.
+type MemoryStore struct {
+	mu    sync.Mutex
+	times map[int]Times
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{times: map[int]Times{}}
+}
+
+func (s *MemoryStore) Get(ids ...int) (map[int]Times, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := map[int]Times{}
+	if len(ids) == 0 {
+		for id, times := range s.times {
+			found[id] = times
+		}
+	}
+	for _, id := range ids {
+		if times, ok := s.times[id]; ok {
+			found[id] = times
+		}
+	}
+
+	return found, nil
+}
+
+func (s *MemoryStore) Put(id int, times Times) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.times[id] = times
+
+	return nil
+}
+
+// Service is a gotodo.Service which records the Times of the Todos it
+// creates, edits, marks as done and deletes.
+type Service struct {
+	gotodo.Service
+	Store Store
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+
+	// OnError is called with the errors of Store. They do not fail the calls
+	// to the Service, as the Todos are changed by then.
+	OnError func(method string, err error)
+}
+
+// NewService returns a Service recording the Times of the Todos of svc in
+// store.
+func NewService(svc gotodo.Service, store Store) *Service {
+	return &Service{Service: svc, Store: store}
+}
+
+// Times returns the Times of the Todos identified by ids, or of every Todo if
+// ids is empty.
+func (s *Service) Times(ids ...int) (map[int]Times, error) {
+	return s.Store.Get(ids...)
+}
+
+func (s *Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+
+	return time.Now()
+}
+
+// record applies set to the Times of the Todo identified by id.
+func (s *Service) record(method string, id int, set func(times *Times)) {
+	found, err := s.Store.Get(id)
+	if err == nil {
+		times := found[id]
+		set(&times)
+		err = s.Store.Put(id, times)
+	}
+
+	if err != nil && s.OnError != nil {
+		s.OnError(method, err)
+	}
+}
+
+func (s *Service) Add(title string, description string) (*gotodo.Todo, error) {
+	todo, err := s.Service.Add(title, description)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	s.record("Add", todo.ID, func(times *Times) {
+		*times = Times{CreatedAt: now, UpdatedAt: now}
+	})
+
+	return todo, nil
+}
+
+func (s *Service) Edit(todo *gotodo.Todo) error {
+	if err := s.Service.Edit(todo); err != nil {
+		return err
+	}
+
+	now := s.now()
+	s.record("Edit", todo.ID, func(times *Times) {
+		times.UpdatedAt = now
+	})
+
+	return nil
+}
+
+func (s *Service) MarkAsDone(todo *gotodo.Todo) error {
+	if err := s.Service.MarkAsDone(todo); err != nil {
+		return err
+	}
+
+	now := s.now()
+	s.record("MarkAsDone", todo.ID, func(times *Times) {
+		if times.CompletedAt.IsZero() {
+			times.CompletedAt = now
+			times.UpdatedAt = now
+		}
+	})
+
+	return nil
+}
+
+func (s *Service) Delete(todo *gotodo.Todo) error {
+	if err := s.Service.Delete(todo); err != nil {
+		return err
+	}
+
+	now := s.now()
+	s.record("Delete", todo.ID, func(times *Times) {
+		times.DeletedAt = now
+	})
+
+	return nil
+}
+
+func (s *Service) DeleteFinished() {
+	finished := s.Service.GetFinished()
+	s.Service.DeleteFinished()
+
+	now := s.now()
+	for _, todo := range finished {
+		s.record("DeleteFinished", todo.ID, func(times *Times) {
+			times.DeletedAt = now
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package timestamps
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saifulwebid/gotodo"
+)
+
+// todoList is the part of a gotodo.Service which Service decorates.
+type todoList struct {
+	gotodo.Service
+	todos []*gotodo.Todo
+}
+
+func (l *todoList) Add(title string, description string) (*gotodo.Todo, error) {
+	if title == "" {
+		return nil, errors.New("title must not be empty")
+	}
+
+	todo := &gotodo.Todo{ID: len(l.todos) + 1, Title: title, Description: description}
+	l.todos = append(l.todos, todo)
+
+	return todo, nil
+}
+
+func (l *todoList) MarkAsDone(todo *gotodo.Todo) error {
+	todo.Done = true
+	l.todos[todo.ID-1].Done = true
+
+	return nil
+}
+
+func (l *todoList) Edit(todo *gotodo.Todo) error {
+	return nil
+}
+
+func (l *todoList) Delete(todo *gotodo.Todo) error {
+	return nil
+}
+
+func (l *todoList) GetFinished() []*gotodo.Todo {
+	var finished []*gotodo.Todo
+	for _, todo := range l.todos {
+		if todo.Done {
+			finished = append(finished, todo)
+		}
+	}
+
+	return finished
+}
+
+func (l *todoList) DeleteFinished() {}
+
+// failingStore is a Store which fails.
+type failingStore struct{}
+
+func (failingStore) Get(ids ...int) (map[int]Times, error) {
+	return nil, errors.New("table todo_times does not exist")
+}
+
+func (failingStore) Put(id int, times Times) error {
+	return errors.New("table todo_times does not exist")
+}
+
+func TestService(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	s := NewService(&todoList{}, NewMemoryStore())
+	s.Now = func() time.Time { return now }
+
+	milk, err := s.Add("Buy milk", "")
+	assert.NoError(t, err)
+	_, err = s.Add("", "")
+	assert.Error(t, err)
+	eggs, _ := s.Add("Buy eggs", "")
+
+	now = now.Add(time.Hour)
+	assert.NoError(t, s.MarkAsDone(milk))
+	assert.NoError(t, s.MarkAsDone(eggs))
+
+	now = now.Add(time.Hour)
+	assert.NoError(t, s.MarkAsDone(milk))
+	assert.NoError(t, s.Edit(milk))
+	assert.NoError(t, s.Delete(eggs))
+
+	now = now.Add(time.Hour)
+	s.DeleteFinished()
+
+	times, err := s.Times()
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]Times{
+		1: {
+			CreatedAt:   time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC),
+			UpdatedAt:   time.Date(2026, 10, 19, 11, 30, 0, 0, time.UTC),
+			CompletedAt: time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC),
+			DeletedAt:   time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC),
+		},
+		2: {
+			CreatedAt:   time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC),
+			UpdatedAt:   time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC),
+			CompletedAt: time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC),
+			DeletedAt:   time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC),
+		},
+	}, times)
+
+	times, err = s.Times(2, 3)
+	assert.NoError(t, err)
+	assert.Len(t, times, 1)
+}
+
+func TestServiceStoreErrors(t *testing.T) {
+	var failed []string
+	s := NewService(&todoList{}, failingStore{})
+	s.OnError = func(method string, err error) {
+		failed = append(failed, method)
+	}
+
+	todo, err := s.Add("Buy milk", "")
+	assert.NoError(t, err)
+	assert.NoError(t, s.MarkAsDone(todo))
+
+	assert.Equal(t, []string{"Add", "MarkAsDone"}, failed)
+}
+
+func TestMySQLDSN(t *testing.T) {
+	assert.Equal(t, "gotodo:secret@tcp(127.0.0.1:3306)/gotodo?parseTime=true",
+		MySQLDSN("127.0.0.1", 3306, "gotodo", "gotodo", "secret"))
+}
+
+func TestWithTimes(t *testing.T) {
+	created := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	todo := WithTimes(&gotodo.Todo{ID: 1, Title: "Buy milk"}, Times{CreatedAt: created, UpdatedAt: created})
+
+	b, err := json.Marshal(todo)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": 1, "title": "Buy milk", "description": "", "done": false,
+		"created_at": "2026-10-19T09:30:00Z", "updated_at": "2026-10-19T09:30:00Z"}`, string(b))
+
+	decoded := &Todo{}
+	assert.NoError(t, json.Unmarshal(b, decoded))
+	assert.Equal(t, "Buy milk", decoded.Title)
+	assert.Equal(t, Times{CreatedAt: created, UpdatedAt: created}, decoded.Times())
+}
+
+func TestParseFilter(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		field, since, until string
+		filter              Filter
+		err                 string
+	}{
+		{"", "", "", Filter{Field: FieldCreated}, ""},
+		{"completed", "2026-10-12", "2026-10-18", Filter{Field: FieldCompleted, Since: time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), Until: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)}, ""},
+		{"updated", "2026-10-19T08:00:00+02:00", "", Filter{Field: FieldUpdated, Since: time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)}, ""},
+		{"", "36h", "", Filter{Field: FieldCreated, Since: time.Date(2026, 10, 17, 21, 30, 0, 0, time.UTC)}, ""},
+		{"", "7d", "1w", Filter{Field: FieldCreated, Since: time.Date(2026, 10, 12, 9, 30, 0, 0, time.UTC), Until: time.Date(2026, 10, 12, 9, 30, 0, 0, time.UTC)}, ""},
+		{"deleted", "", "", Filter{}, `timestamp "deleted" must be either created, updated or completed`},
+		{"", "yesterday", "", Filter{}, `since: "yesterday" must be a time`},
+		{"", "", "-3d", Filter{}, `until: "-3d" must be a time`},
+	} {
+		filter, err := ParseFilter(tc.field, tc.since, tc.until, now)
+
+		if tc.err != "" {
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.err)
+			}
+			continue
+		}
+		if assert.NoError(t, err) {
+			assert.True(t, tc.filter.Since.Equal(filter.Since), tc.since)
+			assert.True(t, tc.filter.Until.Equal(filter.Until), tc.until)
+			assert.Equal(t, tc.filter.Field, filter.Field)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2026, 10, n, 12, 0, 0, 0, time.UTC) }
+	todos := []*gotodo.Todo{{ID: 1}, {ID: 2}, {ID: 3}}
+	times := map[int]Times{
+		1: {CreatedAt: day(10), CompletedAt: day(18)},
+		2: {CreatedAt: day(17)},
+	}
+
+	f := Filter{Field: FieldCreated, Since: day(15)}
+	assert.Equal(t, []*gotodo.Todo{todos[1]}, f.Apply(todos, times))
+
+	f = Filter{Field: FieldCompleted, Until: day(19)}
+	assert.Equal(t, []*gotodo.Todo{todos[0]}, f.Apply(todos, times))
+
+	f = Filter{Field: FieldCreated, Since: day(1), Until: day(10)}
+	assert.Empty(t, f.Apply(todos, times))
+
+	assert.Equal(t, todos, Filter{}.Apply(todos, times))
+}
@@ -0,0 +1,54 @@
+// Package version holds build information of gotodoapp binaries.
+//
+// Commit and BuildTime are meant to be set at build time, e.g.:
+//
+//	go build -ldflags "-X github.com/saifulwebid/gotodoapp/version.Commit=$(git rev-parse HEAD) -X github.com/saifulwebid/gotodoapp/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./app/gotodoserver
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	// Commit is the git commit the binary is built from.
+	Commit = ""
+
+	// BuildTime is the time the binary is built at.
+	BuildTime = ""
+)
+
+// Info describes the build of the running binary.
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build information of the running binary. If Commit is not
+// set at build time, it is taken from the information embedded by the Go
+// toolchain, if any.
+func Get() Info {
+	info := Info{
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok && info.Commit == "" {
+		for _, setting := range bi.Settings {
+			if setting.Key == "vcs.revision" {
+				info.Commit = setting.Value
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+
+	return info
+}